@@ -0,0 +1,74 @@
+package api
+
+import (
+	"assignment1/service"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"strconv"
+	"strings"
+)
+
+type AdminHandler struct {
+	Service *service.RateService
+}
+
+func NewAdminHandler(rs *service.RateService) *AdminHandler {
+	return &AdminHandler{Service: rs}
+}
+
+type overrideRequest struct {
+	Rate   decimal.Decimal `json:"rate"`
+	Reason string          `json:"reason"`
+	// ExpiresAt is an RFC3339 timestamp or epoch seconds.
+	ExpiresAt string `json:"expires_at"`
+}
+
+func (h *AdminHandler) SetOverride(c *gin.Context) {
+	var req overrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "body", "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		respondInvalidInput(c, "reason", "Missing reason")
+		return
+	}
+	expiresAt, err := parseTimestamp(req.ExpiresAt)
+	if err != nil || expiresAt == 0 {
+		respondInvalidInput(c, "expires_at", "Missing or invalid expires_at, expected RFC3339 or epoch seconds")
+		return
+	}
+
+	data, err := h.Service.SetOverride(c.Request.Context(), c.Param("base"), c.Param("target"), req.Rate, strings.TrimSpace(req.Reason), expiresAt)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Override saved successfully")
+}
+
+func (h *AdminHandler) ListOverrides(c *gin.Context) {
+	includeExpired := false
+	if value := c.Query("include_expired"); value != "" {
+		var err error
+		if includeExpired, err = strconv.ParseBool(value); err != nil {
+			respondInvalidInput(c, "include_expired", "Invalid include_expired parameter, expected true or false")
+			return
+		}
+	}
+
+	data, err := h.Service.ListOverrides(c.Request.Context(), includeExpired)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Overrides fetched successfully")
+}
+
+func (h *AdminHandler) DeleteOverride(c *gin.Context) {
+	if err := h.Service.DeleteOverride(c.Request.Context(), c.Param("base"), c.Param("target")); err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, nil, "Override removed successfully")
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"assignment1/middleware"
+	"assignment1/service"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type AlertHandler struct {
+	Service *service.RateService
+}
+
+func NewAlertHandler(rs *service.RateService) *AlertHandler {
+	return &AlertHandler{Service: rs}
+}
+
+type alertRequest struct {
+	Base      string          `json:"base"`
+	Target    string          `json:"target"`
+	Condition string          `json:"condition"`
+	Threshold decimal.Decimal `json:"threshold"`
+	// Window is seconds or a duration such as "1h", for change alerts.
+	Window      string `json:"window"`
+	CallbackURL string `json:"callback_url"`
+}
+
+// alertClient returns the client owning the alerts: the one authenticated by
+// the request's API key. Anonymous callers are refused with 401, and the
+// service scopes every lookup to the client so another client's alert is
+// reported as not found.
+func alertClient(c *gin.Context) (string, bool) {
+	client := strings.TrimSpace(clientID(c))
+	if client == "" {
+		RespondError(c, http.StatusUnauthorized, "Alerts require an "+middleware.APIKeyHeader+" header")
+		return "", false
+	}
+	return client, true
+}
+
+func alertID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		respondInvalidInput(c, "id", "Invalid alert id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	client, ok := alertClient(c)
+	if !ok {
+		return
+	}
+	var req alertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "body", "Invalid request body")
+		return
+	}
+	window, err := parseDuration("window", req.Window)
+	if err != nil {
+		respondInvalidInput(c, "window", err.Error())
+		return
+	}
+
+	data, err := h.Service.CreateAlert(c.Request.Context(), client, service.AlertRequest{
+		Base:        req.Base,
+		Target:      req.Target,
+		Condition:   strings.ToLower(strings.TrimSpace(req.Condition)),
+		Threshold:   req.Threshold,
+		Window:      int64(window.Seconds()),
+		CallbackURL: strings.TrimSpace(req.CallbackURL),
+	})
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Alert created successfully")
+}
+
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	client, ok := alertClient(c)
+	if !ok {
+		return
+	}
+	data, err := h.Service.ListAlerts(c.Request.Context(), client)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Alerts fetched successfully")
+}
+
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	client, ok := alertClient(c)
+	if !ok {
+		return
+	}
+	id, ok := alertID(c)
+	if !ok {
+		return
+	}
+	if err := h.Service.DeleteAlert(c.Request.Context(), client, id); err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, nil, "Alert removed successfully")
+}
+
+func (h *AlertHandler) ListDeliveries(c *gin.Context) {
+	client, ok := alertClient(c)
+	if !ok {
+		return
+	}
+	id, ok := alertID(c)
+	if !ok {
+		return
+	}
+	data, err := h.Service.ListAlertDeliveries(c.Request.Context(), client, id)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Deliveries fetched successfully")
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"assignment1/db"
+	"assignment1/middleware"
+	"assignment1/models"
+	"assignment1/service"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func useTestDB(t *testing.T) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000"
+	conn, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.RateAlert{}, &models.AlertDelivery{}); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+	previous := db.DB
+	db.DB = conn
+	t.Cleanup(func() {
+		db.DB = previous
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+func alertRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ClientAuth(middleware.NewClientKeys(map[string]string{"key-acme": "acme", "key-globex": "globex"})))
+	h := NewAlertHandler(&service.RateService{})
+	r.POST("/alerts", h.CreateAlert)
+	r.GET("/alerts", h.ListAlerts)
+	r.DELETE("/alerts/:id", h.DeleteAlert)
+	r.GET("/alerts/:id/deliveries", h.ListDeliveries)
+	return r
+}
+
+func serveAlerts(r *gin.Engine, method, path, apiKey string) (int, APIResponse) {
+	req := httptest.NewRequest(method, path, nil)
+	if apiKey != "" {
+		req.Header.Set(middleware.APIKeyHeader, apiKey)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp APIResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestAlertsRequireAPIKey(t *testing.T) {
+	r := alertRouter()
+	for _, route := range [][2]string{
+		{http.MethodPost, "/alerts"},
+		{http.MethodGet, "/alerts"},
+		{http.MethodDelete, "/alerts/1"},
+		{http.MethodGet, "/alerts/1/deliveries"},
+	} {
+		if status, resp := serveAlerts(r, route[0], route[1], ""); status != http.StatusUnauthorized || resp.Error == "" {
+			t.Errorf("anonymous %s %s = %d %+v, want 401", route[0], route[1], status, resp)
+		}
+	}
+}
+
+func TestAlertsAreScopedToTheirClient(t *testing.T) {
+	useTestDB(t)
+	alert := models.RateAlert{ClientID: "acme", Base: "USD", Target: "EUR", Condition: models.AlertAbove, CallbackURL: "https://acme.example/hook"}
+	if err := db.DB.Create(&alert).Error; err != nil {
+		t.Fatalf("creating alert: %v", err)
+	}
+	r := alertRouter()
+
+	status, resp := serveAlerts(r, http.MethodGet, "/alerts", "key-globex")
+	if alerts, _ := resp.Data.([]interface{}); status != http.StatusOK || len(alerts) != 0 {
+		t.Errorf("another client's alert list = %d %+v, want none", status, resp.Data)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		path := "/alerts/1"
+		if method == http.MethodGet {
+			path += "/deliveries"
+		}
+		if status, resp := serveAlerts(r, method, path, "key-globex"); status != http.StatusNotFound || resp.Code != "NOT_FOUND" {
+			t.Errorf("another client's %s %s = %d %+v, want 404", method, path, status, resp)
+		}
+	}
+
+	status, resp = serveAlerts(r, http.MethodGet, "/alerts", "key-acme")
+	if alerts, _ := resp.Data.([]interface{}); status != http.StatusOK || len(alerts) != 1 {
+		t.Errorf("owner's alert list = %d %+v, want its alert", status, resp.Data)
+	}
+	if status, _ := serveAlerts(r, http.MethodDelete, "/alerts/1", "key-acme"); status != http.StatusOK {
+		t.Errorf("owner deleting its alert = %d, want 200", status)
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"assignment1/service"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+type CurrencyHandler struct {
+	Service *service.CurrencyService
+}
+
+func NewCurrencyHandler(cs *service.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{Service: cs}
+}
+
+func (h *CurrencyHandler) ListCurrencies(c *gin.Context) {
+	var filter service.CurrencyFilter
+	var err error
+
+	if active := c.Query("active"); active != "" {
+		if filter.ActiveOnly, err = strconv.ParseBool(active); err != nil {
+			respondInvalidInput(c, "active", "Invalid active parameter, expected true or false")
+			return
+		}
+	}
+	if supported := c.Query("supported"); supported != "" {
+		if filter.SupportedOnly, err = strconv.ParseBool(supported); err != nil {
+			respondInvalidInput(c, "supported", "Invalid supported parameter, expected true or false")
+			return
+		}
+	}
+
+	data, err := h.Service.ListCurrencies(c.Request.Context(), filter)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Currencies fetched successfully")
+}
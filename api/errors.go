@@ -0,0 +1,106 @@
+package api
+
+import (
+	"assignment1/service"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"log"
+	"net/http"
+)
+
+// errorDomain is the ErrorInfo domain of gRPC errors raised by this service.
+const errorDomain = "rate"
+
+type errorStatus struct {
+	HTTP int
+	GRPC codes.Code
+}
+
+var errorStatuses = map[service.ErrorKind]errorStatus{
+	service.ErrInvalidInput:    {http.StatusBadRequest, codes.InvalidArgument},
+	service.ErrUnknownCurrency: {http.StatusBadRequest, codes.InvalidArgument},
+	service.ErrRateUnavailable: {http.StatusNotFound, codes.NotFound},
+	service.ErrNotFound:        {http.StatusNotFound, codes.NotFound},
+	service.ErrStaleRate:       {http.StatusServiceUnavailable, codes.Unavailable},
+	service.ErrUpstream:        {http.StatusServiceUnavailable, codes.Unavailable},
+}
+
+// statusOf maps err to its HTTP status and gRPC code. Errors the service did
+// not classify are internal errors.
+func statusOf(err error) errorStatus {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorStatus{http.StatusGatewayTimeout, codes.DeadlineExceeded}
+	case errors.Is(err, context.Canceled):
+		return errorStatus{http.StatusServiceUnavailable, codes.Canceled}
+	}
+	if s, ok := errorStatuses[service.KindOf(err)]; ok {
+		return s
+	}
+	return errorStatus{http.StatusInternalServerError, codes.Internal}
+}
+
+// RespondServiceError writes err with the status for its kind, along with
+// the kind as code and any structured details.
+func RespondServiceError(c *gin.Context, err error) {
+	s := statusOf(err)
+	if s.HTTP == http.StatusInternalServerError {
+		log.Printf("API Error: Unclassified error, error: %v", err)
+	}
+	c.JSON(s.HTTP, APIResponse{
+		Success: false,
+		Error:   err.Error(),
+		Code:    string(service.KindOf(err)),
+		Details: service.ErrorMetadata(err),
+	})
+}
+
+// grpcError converts err to a gRPC status carrying an ErrorInfo detail with
+// the error kind as reason, plus a BadRequest field violation for invalid
+// input.
+func grpcError(err error) error {
+	s := statusOf(err)
+	if s.GRPC == codes.Internal {
+		log.Printf("gRPC Error: Unclassified error, error: %v", err)
+	}
+	st := status.New(s.GRPC, err.Error())
+
+	kind := service.KindOf(err)
+	if kind == "" {
+		return st.Err()
+	}
+	metadata := service.ErrorMetadata(err)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(kind), Domain: errorDomain, Metadata: metadata}}
+	if field := metadata["field"]; field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: err.Error()}},
+		})
+	}
+	return withDetails(st, details...)
+}
+
+// respondInvalidInput writes the error for a request rejected before
+// reaching the service.
+func respondInvalidInput(c *gin.Context, field, message string) {
+	RespondServiceError(c, service.InvalidInput(field, "%s", message))
+}
+
+// invalidArgument is the gRPC error for a request rejected before reaching
+// the service.
+func invalidArgument(field, message string) error {
+	return grpcError(service.InvalidInput(field, "%s", message))
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		log.Printf("gRPC Error: Failed to attach error details, error: %v", err)
+		return st.Err()
+	}
+	return detailed.Err()
+}
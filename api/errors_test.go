@@ -0,0 +1,109 @@
+package api
+
+import (
+	ratepb "assignment1/grpc/proto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func validationRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rates := NewRateHandler(nil)
+	admin := NewAdminHandler(nil)
+	stream := NewStreamHandler(nil, StreamConfig{})
+	r.GET("/rate", rates.GetRate)
+	r.GET("/rates", rates.GetRateMatrix)
+	r.GET("/rates/history", rates.GetRateHistory)
+	r.POST("/rates/batch", rates.GetRates)
+	r.GET("/rates/stream", stream.StreamEvents)
+	r.GET("/convert", rates.Convert)
+	r.GET("/currencies", NewCurrencyHandler(nil).ListCurrencies)
+	r.GET("/admin/overrides", admin.ListOverrides)
+	r.PUT("/admin/overrides/:base/:target", admin.SetOverride)
+	return r
+}
+
+func TestHandlerValidationErrorsCarryField(t *testing.T) {
+	r := validationRouter()
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		field  string
+	}{
+		{http.MethodGet, "/rate?base=USD", "", "base"},
+		{http.MethodGet, "/rate?base=USD&target=EUR&as_of=yesterday", "", "as_of"},
+		{http.MethodGet, "/rate?base=USD&target=EUR&precision=many", "", "precision"},
+		{http.MethodGet, "/rate?base=USD&target=EUR&max_age=old", "", "max_age"},
+		{http.MethodGet, "/rates", "", "base"},
+		{http.MethodGet, "/rates/history?base=USD&target=EUR", "", "from"},
+		{http.MethodGet, "/rates/history?base=USD&target=EUR&from=1000&to=later", "", "to"},
+		{http.MethodPost, "/rates/batch", "{", "body"},
+		{http.MethodPost, "/rates/batch", `{"pairs": []}`, "pairs"},
+		{http.MethodPost, "/rates/batch", `{"pairs": [{"base": "USD"}]}`, "pairs"},
+		{http.MethodGet, "/rates/stream?pairs=USDEUR", "", "pairs"},
+		{http.MethodGet, "/rates/stream", "", "pairs"},
+		{http.MethodGet, "/rates/stream?pairs=USD_EUR&last_event_id=latest", "", "last_event_id"},
+		{http.MethodGet, "/convert?from=USD&to=EUR", "", "from"},
+		{http.MethodGet, "/convert?from=USD&to=EUR&amount=ten", "", "amount"},
+		{http.MethodGet, "/convert?from=USD&to=EUR&amount=10&rounding=sideways", "", "rounding"},
+		{http.MethodGet, "/currencies?active=maybe", "", "active"},
+		{http.MethodGet, "/admin/overrides?include_expired=maybe", "", "include_expired"},
+		{http.MethodPut, "/admin/overrides/USD/EUR", `{"rate": "1.1", "expires_at": "2030-01-01T00:00:00Z"}`, "reason"},
+		{http.MethodPut, "/admin/overrides/USD/EUR", `{"rate": "1.1", "reason": "outage"}`, "expires_at"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%s %s: decoding response %q: %v", tt.method, tt.path, w.Body.String(), err)
+			continue
+		}
+		if w.Code != http.StatusBadRequest || resp.Code != "INVALID_INPUT" || resp.Details["field"] != tt.field || resp.Error == "" {
+			t.Errorf("%s %s = %d %+v, want 400 INVALID_INPUT for field %s", tt.method, tt.path, w.Code, resp, tt.field)
+		}
+	}
+}
+
+func TestValidationErrorsMatchAcrossTransports(t *testing.T) {
+	w := httptest.NewRecorder()
+	validationRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rates/batch", bytes.NewBufferString(`{"pairs": []}`)))
+	var rest APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &rest); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	_, err := NewRateGRPCServer(nil, nil).GetRates(context.Background(), &ratepb.GetRatesRequest{})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != rest.Error {
+		t.Fatalf("gRPC error = %s %q, want InvalidArgument %q", st.Code(), st.Message(), rest.Error)
+	}
+	var info *errdetails.ErrorInfo
+	var violation *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			violation = d
+		}
+	}
+	if info == nil || info.Reason != rest.Code || info.Metadata["field"] != rest.Details["field"] {
+		t.Errorf("gRPC ErrorInfo = %v, want reason %s for field %s", info, rest.Code, rest.Details["field"])
+	}
+	if violation == nil || len(violation.FieldViolations) != 1 || violation.FieldViolations[0].Field != rest.Details["field"] {
+		t.Errorf("gRPC BadRequest = %v, want a violation for field %s", violation, rest.Details["field"])
+	}
+}
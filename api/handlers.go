@@ -1,11 +1,41 @@
 package api
 
 import (
+	"assignment1/middleware"
 	"assignment1/service"
 	"github.com/gin-gonic/gin"
+	"log"
 )
 
-func RegisterRoutes(router *gin.Engine, rs *service.RateService) {
+// RegisterRoutes mounts the public API. The admin API is only mounted when an
+// admin token is configured.
+func RegisterRoutes(router *gin.Engine, rs *service.RateService, cs *service.CurrencyService, ss *service.StatusService, adminToken string, stream StreamConfig) {
 	rateHandler := NewRateHandler(rs)
+	currencyHandler := NewCurrencyHandler(cs)
+	statusHandler := NewStatusHandler(ss)
+	streamHandler := NewStreamHandler(rs, stream)
+	alertHandler := NewAlertHandler(rs)
 	router.GET("/rate", rateHandler.GetRate)
+	router.GET("/rates", rateHandler.GetRateMatrix)
+	router.GET("/rates/history", rateHandler.GetRateHistory)
+	router.POST("/rates/batch", rateHandler.GetRates)
+	router.GET("/rates/stream", streamHandler.StreamEvents)
+	router.GET("/rates/ws", streamHandler.StreamWebSocket)
+	router.GET("/convert", rateHandler.Convert)
+	router.GET("/currencies", currencyHandler.ListCurrencies)
+	router.GET("/status/providers", statusHandler.GetProviderStatus)
+	router.POST("/alerts", alertHandler.CreateAlert)
+	router.GET("/alerts", alertHandler.ListAlerts)
+	router.DELETE("/alerts/:id", alertHandler.DeleteAlert)
+	router.GET("/alerts/:id/deliveries", alertHandler.ListDeliveries)
+
+	if adminToken == "" {
+		log.Printf("Admin API disabled, ADMIN_TOKEN is not set")
+		return
+	}
+	adminHandler := NewAdminHandler(rs)
+	admin := router.Group("/admin", middleware.AdminAuth(adminToken))
+	admin.GET("/overrides", adminHandler.ListOverrides)
+	admin.PUT("/overrides/:base/:target", adminHandler.SetOverride)
+	admin.DELETE("/overrides/:base/:target", adminHandler.DeleteOverride)
 }
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"assignment1/middleware"
+	"assignment1/models"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseTimestamp accepts either an RFC3339 timestamp or epoch seconds and
+// returns epoch seconds. An empty value yields 0; timestamps at or before the
+// Unix epoch are rejected.
+func parseTimestamp(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	secs, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, err
+		}
+		secs = t.Unix()
+	}
+	if secs <= 0 {
+		return 0, fmt.Errorf("timestamp %q is not after the Unix epoch", value)
+	}
+	return secs, nil
+}
+
+// parseMaxAge accepts seconds or a Go duration such as "15m". An empty
+// value yields 0.
+func parseMaxAge(value string) (time.Duration, error) {
+	return parseDuration("max_age", value)
+}
+
+// parseDuration parses the named parameter as seconds or a Go duration. An
+// empty value yields 0.
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected seconds or a duration such as 15m", name, value)
+	}
+	return d, nil
+}
+
+// parsePairs parses comma separated BASE_TARGET pairs, e.g. "USD_EUR,EUR_GBP".
+func parsePairs(values ...string) ([]models.RatePair, error) {
+	var pairs []models.RatePair
+	for _, value := range values {
+		for _, raw := range strings.Split(value, ",") {
+			raw = strings.TrimSpace(raw)
+			if raw == "" {
+				continue
+			}
+			base, target, ok := strings.Cut(raw, "_")
+			if !ok || base == "" || target == "" {
+				return nil, fmt.Errorf("invalid pair %q, expected BASE_TARGET", raw)
+			}
+			pairs = append(pairs, models.RatePair{Base: base, Target: target})
+		}
+	}
+	return pairs, nil
+}
+
+// clientID returns the client authenticated by the request's API key, or ""
+// for anonymous callers.
+func clientID(c *gin.Context) string {
+	return middleware.ClientFromContext(c.Request.Context())
+}
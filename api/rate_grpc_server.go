@@ -1,19 +1,36 @@
 package api
 
 import (
+	"assignment1/currency"
 	ratepb "assignment1/grpc/proto"
+	"assignment1/middleware"
+	"assignment1/models"
 	"assignment1/service"
+	"assignment1/utility"
 	"context"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
+	"strconv"
+	"time"
 )
 
 type RateGRPCServer struct {
 	ratepb.UnimplementedRateServiceServer
-	Service *service.RateService
+	Service    *service.RateService
+	Currencies *service.CurrencyService
 }
 
-func NewRateGRPCServer(svc *service.RateService) *RateGRPCServer {
-	return &RateGRPCServer{Service: svc}
+func NewRateGRPCServer(svc *service.RateService, currencies *service.CurrencyService) *RateGRPCServer {
+	return &RateGRPCServer{Service: svc, Currencies: currencies}
+}
+
+// grpcClientID returns the client authenticated by the x-api-key metadata
+// key (see middleware.GRPCClientAuth), or "" for anonymous callers.
+func grpcClientID(ctx context.Context) string {
+	return middleware.ClientFromContext(ctx)
 }
 
 func (s *RateGRPCServer) GetRate(ctx context.Context, req *ratepb.GetRateRequest) (*ratepb.GetRateResponse, error) {
@@ -22,26 +39,282 @@ func (s *RateGRPCServer) GetRate(ctx context.Context, req *ratepb.GetRateRequest
 	resp := &ratepb.GetRateResponse{}
 
 	if base == "" || target == "" {
-		resp.Error = "Missing base or target parameter"
-		return resp, nil
+		return nil, invalidArgument("base", "Missing base or target parameter")
 	}
 
 	// Add this log
 	log.Printf("GetRate called with base=%s, target=%s", base, target)
 
-	data, err := s.Service.GetRate(base, target)
+	precision, err := currency.ParsePrecision(req.GetPrecision())
+	if err != nil {
+		return nil, invalidArgument("precision", err.Error())
+	}
+
+	data, err := s.Service.GetRate(ctx, base, target, service.RateOptions{
+		AsOf:      req.GetAsOf(),
+		Precision: precision,
+		Client:    grpcClientID(ctx),
+		MaxAge:    time.Duration(req.GetMaxAgeSeconds()) * time.Second,
+	})
 
 	// Add this log
 	log.Printf("GetRate finished: err=%v, data=%+v", err, data)
 
 	if err != nil {
-		resp.Error = err.Error()
-		return resp, nil
+		return nil, grpcError(err)
 	}
 
 	resp.Base = data.Base
 	resp.Target = data.Target
-	resp.Rate = data.Rate
+	resp.Rate = data.Rate.InexactFloat64()
+	resp.RateDecimal = data.Rate.String()
+	resp.BidDecimal = data.Bid.String()
+	resp.AskDecimal = data.Ask.String()
+	resp.MidDecimal = data.Mid.String()
+	resp.Provider = data.Provider
+	resp.UpdatedAt = data.UpdatedAt
+	resp.AgeSeconds = data.Age
+	resp.Stale = data.Stale
+	resp.Path = data.Path
+	return resp, nil
+}
+
+func (s *RateGRPCServer) GetRates(ctx context.Context, req *ratepb.GetRatesRequest) (*ratepb.GetRatesResponse, error) {
+	resp := &ratepb.GetRatesResponse{}
+
+	if len(req.GetPairs()) == 0 {
+		return nil, invalidArgument("pairs", "Missing pairs")
+	}
+	if len(req.GetPairs()) > service.MaxBatchPairs {
+		return nil, invalidArgument("pairs", fmt.Sprintf("Too many pairs, at most %d allowed", service.MaxBatchPairs))
+	}
+
+	precision, err := currency.ParsePrecision(req.GetPrecision())
+	if err != nil {
+		return nil, invalidArgument("precision", err.Error())
+	}
+
+	pairs := make([]models.RatePair, 0, len(req.GetPairs()))
+	for _, pair := range req.GetPairs() {
+		if pair.GetBase() == "" || pair.GetTarget() == "" {
+			return nil, invalidArgument("pairs", "Missing base or target in pairs")
+		}
+		pairs = append(pairs, models.RatePair{Base: pair.GetBase(), Target: pair.GetTarget()})
+	}
+
+	log.Printf("GetRates called with %d pairs", len(pairs))
+
+	for _, result := range s.Service.GetRates(ctx, pairs, precision, grpcClientID(ctx)) {
+		item := &ratepb.RateResult{
+			Base:      result.Base,
+			Target:    result.Target,
+			Error:     result.Error,
+			ErrorCode: result.ErrorCode,
+		}
+		if result.Data != nil {
+			item.RateDecimal = result.Data.Rate.String()
+			item.BidDecimal = result.Data.Bid.String()
+			item.AskDecimal = result.Data.Ask.String()
+			item.MidDecimal = result.Data.Mid.String()
+			item.UpdatedAt = result.Data.UpdatedAt
+			item.Provider = result.Data.Provider
+			item.AgeSeconds = result.Data.Age
+			item.Stale = result.Data.Stale
+			item.Path = result.Data.Path
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	return resp, nil
+}
+
+func (s *RateGRPCServer) GetRateHistory(req *ratepb.GetRateHistoryRequest, stream ratepb.RateService_GetRateHistoryServer) error {
+	base := req.GetBase()
+	target := req.GetTarget()
+	if base == "" || target == "" {
+		return invalidArgument("base", "Missing base or target parameter")
+	}
+	if req.GetFrom() == 0 {
+		return invalidArgument("from", "Missing from parameter")
+	}
+
+	to := req.GetTo()
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+
+	interval, err := service.ParseHistoryInterval(req.GetInterval())
+	if err != nil {
+		return grpcError(err)
+	}
+
+	log.Printf("GetRateHistory called with base=%s, target=%s, from=%d, to=%d, interval=%s", base, target, req.GetFrom(), to, interval)
+
+	points, err := s.Service.GetRateHistory(stream.Context(), base, target, req.GetFrom(), to, interval)
+	if err != nil {
+		return grpcError(err)
+	}
+
+	for _, point := range points {
+		err := stream.Send(&ratepb.RatePoint{
+			Base:        point.Base,
+			Target:      point.Target,
+			Time:        point.Time,
+			Rate:        point.Rate.InexactFloat64(),
+			RateDecimal: point.Rate.String(),
+			UpdatedAt:   point.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RateGRPCServer) Convert(ctx context.Context, req *ratepb.ConvertRequest) (*ratepb.ConvertResponse, error) {
+	from := req.GetFrom()
+	to := req.GetTo()
+	resp := &ratepb.ConvertResponse{}
+
+	if from == "" || to == "" {
+		return nil, invalidArgument("from", "Missing from or to parameter")
+	}
+
+	var mode utility.RoundingMode
+	if rounding := req.GetRoundingMode(); rounding != "" {
+		var err error
+		mode, err = utility.ParseRoundingMode(rounding)
+		if err != nil {
+			return nil, invalidArgument("rounding_mode", err.Error())
+		}
+	}
+
+	amount := decimal.NewFromFloat(req.GetAmount())
+	if raw := req.GetAmountDecimal(); raw != "" {
+		var err error
+		if amount, err = decimal.NewFromString(raw); err != nil {
+			return nil, invalidArgument("amount_decimal", "Invalid amount_decimal parameter")
+		}
+	}
+
+	log.Printf("Convert called with from=%s, to=%s, amount=%s", from, to, amount)
+
+	data, err := s.Service.Convert(ctx, from, to, amount, mode)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp.From = data.From
+	resp.To = data.To
+	resp.Amount = data.Amount.InexactFloat64()
+	resp.AmountDecimal = data.Amount.String()
+	resp.Rate = data.Rate.InexactFloat64()
+	resp.RateDecimal = data.Rate.String()
+	resp.ConvertedAmount = data.ConvertedAmount.InexactFloat64()
+	resp.ConvertedAmountDecimal = data.ConvertedAmount.String()
+	resp.ResultDecimal = data.Result
+	resp.Result, _ = strconv.ParseFloat(data.Result, 64)
+	resp.MinorUnits = int32(data.MinorUnits)
+	resp.RoundingMode = data.RoundingMode
 	resp.UpdatedAt = data.UpdatedAt
 	return resp, nil
 }
+
+func (s *RateGRPCServer) ListCurrencies(ctx context.Context, req *ratepb.ListCurrenciesRequest) (*ratepb.ListCurrenciesResponse, error) {
+	resp := &ratepb.ListCurrenciesResponse{}
+
+	data, err := s.Currencies.ListCurrencies(ctx, service.CurrencyFilter{
+		ActiveOnly:    req.GetActiveOnly(),
+		SupportedOnly: req.GetSupportedOnly(),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	for _, c := range data {
+		currency := &ratepb.Currency{
+			Code:        c.Code,
+			Name:        c.Name,
+			NumericCode: c.NumericCode,
+			Symbol:      c.Symbol,
+			Countries:   c.Countries,
+			Active:      c.Active,
+			Iso:         c.ISO,
+			Supported:   c.Supported,
+		}
+		if c.MinorUnits != nil {
+			minorUnits := int32(*c.MinorUnits)
+			currency.MinorUnits = &minorUnits
+		}
+		resp.Currencies = append(resp.Currencies, currency)
+	}
+	return resp, nil
+}
+
+func (s *RateGRPCServer) SubscribeRates(req *ratepb.SubscribeRequest, stream ratepb.RateService_SubscribeRatesServer) error {
+	if len(req.GetPairs()) == 0 {
+		return invalidArgument("pairs", "Missing pairs")
+	}
+	pairs := make([]models.RatePair, 0, len(req.GetPairs()))
+	for _, pair := range req.GetPairs() {
+		if pair.GetBase() == "" || pair.GetTarget() == "" {
+			return invalidArgument("pairs", "Missing base or target in pairs")
+		}
+		pairs = append(pairs, models.RatePair{Base: pair.GetBase(), Target: pair.GetTarget()})
+	}
+
+	precision, err := currency.ParsePrecision(req.GetPrecision())
+	if err != nil {
+		return invalidArgument("precision", err.Error())
+	}
+
+	ctx := stream.Context()
+	sub, err := s.Service.Subscribe(pairs, req.GetLastId())
+	if err != nil {
+		return grpcError(err)
+	}
+	defer sub.Close()
+	log.Printf("SubscribeRates called with %d pairs", len(pairs))
+
+	// Subscribe before taking the snapshot so no change in between is lost.
+	opts := service.RateOptions{Precision: precision, Client: grpcClientID(ctx)}
+	for _, update := range s.Service.InitialUpdates(ctx, sub, pairs, opts) {
+		if err := stream.Send(rateUpdateMessage(update)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Subscriber fell behind, resubscribe to continue")
+			}
+			if err := stream.Send(rateUpdateMessage(s.Service.QuoteUpdate(update, opts))); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func rateUpdateMessage(update models.RateUpdateDto) *ratepb.RateUpdate {
+	data := update.Rate
+	msg := &ratepb.RateUpdate{
+		Id:          update.ID,
+		Base:        data.Base,
+		Target:      data.Target,
+		RateDecimal: data.Rate.String(),
+		BidDecimal:  data.Bid.String(),
+		AskDecimal:  data.Ask.String(),
+		MidDecimal:  data.Mid.String(),
+		Provider:    data.Provider,
+		UpdatedAt:   data.UpdatedAt,
+		Path:        data.Path,
+		Snapshot:    update.Snapshot,
+	}
+	if update.PreviousRate != nil {
+		msg.PreviousRateDecimal = update.PreviousRate.String()
+	}
+	return msg
+}
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"assignment1/currency"
+	"assignment1/models"
 	"assignment1/service"
+	"assignment1/utility"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"github.com/shopspring/decimal"
+	"time"
 )
 
 type RateHandler struct {
@@ -18,14 +23,162 @@ func (h *RateHandler) GetRate(c *gin.Context) {
 	base := c.Query("base")
 	target := c.Query("target")
 	if base == "" || target == "" {
-		RespondError(c, http.StatusBadRequest, "Missing base or target parameter")
+		respondInvalidInput(c, "base", "Missing base or target parameter")
 		return
 	}
 
-	data, err := h.Service.GetRate(base, target)
+	asOf, err := parseTimestamp(c.Query("as_of"))
 	if err != nil {
-		RespondError(c, http.StatusNotFound, err.Error())
+		respondInvalidInput(c, "as_of", "Invalid as_of parameter, expected RFC3339 or epoch seconds")
+		return
+	}
+
+	precision, err := currency.ParsePrecision(c.Query("precision"))
+	if err != nil {
+		respondInvalidInput(c, "precision", err.Error())
+		return
+	}
+
+	maxAge, err := parseMaxAge(c.Query("max_age"))
+	if err != nil {
+		respondInvalidInput(c, "max_age", err.Error())
+		return
+	}
+
+	data, err := h.Service.GetRate(c.Request.Context(), base, target, service.RateOptions{AsOf: asOf, Precision: precision, Client: clientID(c), MaxAge: maxAge})
+	if err != nil {
+		RespondServiceError(c, err)
 		return
 	}
 	RespondSuccess(c, data, "Rate fetched successfully")
 }
+
+func (h *RateHandler) GetRateHistory(c *gin.Context) {
+	base := c.Query("base")
+	target := c.Query("target")
+	if base == "" || target == "" {
+		respondInvalidInput(c, "base", "Missing base or target parameter")
+		return
+	}
+
+	from, err := parseTimestamp(c.Query("from"))
+	if err != nil || from == 0 {
+		respondInvalidInput(c, "from", "Missing or invalid from parameter, expected RFC3339 or epoch seconds")
+		return
+	}
+	to, err := parseTimestamp(c.Query("to"))
+	if err != nil {
+		respondInvalidInput(c, "to", "Invalid to parameter, expected RFC3339 or epoch seconds")
+		return
+	}
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+
+	interval, err := service.ParseHistoryInterval(c.Query("interval"))
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+
+	points, err := h.Service.GetRateHistory(c.Request.Context(), base, target, from, to, interval)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, points, "Rate history fetched successfully")
+}
+
+func (h *RateHandler) Convert(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+	rawAmount := c.Query("amount")
+	if from == "" || to == "" || rawAmount == "" {
+		respondInvalidInput(c, "from", "Missing from, to or amount parameter")
+		return
+	}
+
+	amount, err := decimal.NewFromString(rawAmount)
+	if err != nil {
+		respondInvalidInput(c, "amount", "Invalid amount parameter")
+		return
+	}
+
+	var mode utility.RoundingMode
+	if rounding := c.Query("rounding"); rounding != "" {
+		mode, err = utility.ParseRoundingMode(rounding)
+		if err != nil {
+			respondInvalidInput(c, "rounding", err.Error())
+			return
+		}
+	}
+
+	data, err := h.Service.Convert(c.Request.Context(), from, to, amount, mode)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Amount converted successfully")
+}
+
+type batchRatesRequest struct {
+	Pairs []struct {
+		Base   string `json:"base"`
+		Target string `json:"target"`
+	} `json:"pairs"`
+	Precision string `json:"precision"`
+}
+
+func (h *RateHandler) GetRates(c *gin.Context) {
+	var req batchRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondInvalidInput(c, "body", "Invalid request body")
+		return
+	}
+	if len(req.Pairs) == 0 {
+		respondInvalidInput(c, "pairs", "Missing pairs")
+		return
+	}
+	if len(req.Pairs) > service.MaxBatchPairs {
+		respondInvalidInput(c, "pairs", fmt.Sprintf("Too many pairs, at most %d allowed", service.MaxBatchPairs))
+		return
+	}
+
+	precision, err := currency.ParsePrecision(req.Precision)
+	if err != nil {
+		respondInvalidInput(c, "precision", err.Error())
+		return
+	}
+
+	pairs := make([]models.RatePair, 0, len(req.Pairs))
+	for _, pair := range req.Pairs {
+		if pair.Base == "" || pair.Target == "" {
+			respondInvalidInput(c, "pairs", "Missing base or target in pairs")
+			return
+		}
+		pairs = append(pairs, models.RatePair{Base: pair.Base, Target: pair.Target})
+	}
+
+	RespondSuccess(c, h.Service.GetRates(c.Request.Context(), pairs, precision, clientID(c)), "Rates fetched successfully")
+}
+
+func (h *RateHandler) GetRateMatrix(c *gin.Context) {
+	base := c.Query("base")
+	if base == "" {
+		respondInvalidInput(c, "base", "Missing base parameter")
+		return
+	}
+
+	precision, err := currency.ParsePrecision(c.Query("precision"))
+	if err != nil {
+		respondInvalidInput(c, "precision", err.Error())
+		return
+	}
+
+	data, err := h.Service.GetRateMatrix(c.Request.Context(), base, precision)
+	if err != nil {
+		RespondServiceError(c, err)
+		return
+	}
+	RespondSuccess(c, data, "Rates fetched successfully")
+}
@@ -10,6 +10,10 @@ type APIResponse struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code is the error kind, e.g. RATE_UNAVAILABLE, and Details its
+	// structured context.
+	Code    string            `json:"code,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
 }
 
 func RespondSuccess(c *gin.Context, data interface{}, message string) {
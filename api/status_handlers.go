@@ -0,0 +1,18 @@
+package api
+
+import (
+	"assignment1/service"
+	"github.com/gin-gonic/gin"
+)
+
+type StatusHandler struct {
+	Service *service.StatusService
+}
+
+func NewStatusHandler(ss *service.StatusService) *StatusHandler {
+	return &StatusHandler{Service: ss}
+}
+
+func (h *StatusHandler) GetProviderStatus(c *gin.Context) {
+	RespondSuccess(c, h.Service.ProviderStatus(), "Provider status fetched successfully")
+}
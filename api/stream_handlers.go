@@ -0,0 +1,318 @@
+package api
+
+import (
+	"assignment1/currency"
+	"assignment1/models"
+	"assignment1/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultStreamHeartbeat = 15 * time.Second
+	streamWriteTimeout     = 10 * time.Second
+	maxStreamRequestBytes  = 16 * 1024
+)
+
+// StreamConfig tunes the SSE and WebSocket rate feeds.
+type StreamConfig struct {
+	// Heartbeat is how often an idle feed is kept alive, defaulting to 15s.
+	Heartbeat time.Duration
+	// AllowedOrigins may open a WebSocket; "*" allows any origin and an empty
+	// list only the server's own.
+	AllowedOrigins []string
+}
+
+type StreamHandler struct {
+	Service   *service.RateService
+	Heartbeat time.Duration
+	upgrader  websocket.Upgrader
+}
+
+func NewStreamHandler(rs *service.RateService, cfg StreamConfig) *StreamHandler {
+	heartbeat := cfg.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultStreamHeartbeat
+	}
+	h := &StreamHandler{Service: rs, Heartbeat: heartbeat}
+	if len(cfg.AllowedOrigins) > 0 {
+		allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+		for _, origin := range cfg.AllowedOrigins {
+			allowed[origin] = true
+		}
+		h.upgrader.CheckOrigin = func(r *http.Request) bool {
+			return allowed["*"] || allowed[r.Header.Get("Origin")]
+		}
+	}
+	return h
+}
+
+// wsRequest changes the pairs of a WebSocket feed, e.g.
+// {"action":"subscribe","pairs":["EUR_GBP"]}.
+type wsRequest struct {
+	Action string   `json:"action"`
+	Pairs  []string `json:"pairs"`
+
+	err error
+}
+
+// wsMessage is sent over a WebSocket feed. Type is "rate" with Update set,
+// "subscribed" or "unsubscribed" with the affected Pairs, or "error".
+type wsMessage struct {
+	Type   string
+	Update *models.RateUpdateDto `json:",omitempty"`
+	Pairs  []string              `json:",omitempty"`
+	Error  string                `json:",omitempty"`
+	Code   string                `json:",omitempty"`
+}
+
+// openFeed parses the pairs, precision and resume point shared by both feeds
+// and subscribes. It responds with an error and returns nil if any is
+// invalid. SSE clients resume with the Last-Event-ID header, which browsers
+// send on reconnect; last_event_id serves clients that cannot set it.
+func (h *StreamHandler) openFeed(c *gin.Context, requirePairs bool) (*service.Subscription, []models.RatePair, service.RateOptions) {
+	pairs, err := parsePairs(c.QueryArray("pairs")...)
+	if err != nil {
+		respondInvalidInput(c, "pairs", err.Error())
+		return nil, nil, service.RateOptions{}
+	}
+	if requirePairs && len(pairs) == 0 {
+		respondInvalidInput(c, "pairs", "Missing pairs parameter")
+		return nil, nil, service.RateOptions{}
+	}
+
+	precision, err := currency.ParsePrecision(c.Query("precision"))
+	if err != nil {
+		respondInvalidInput(c, "precision", err.Error())
+		return nil, nil, service.RateOptions{}
+	}
+
+	var lastID uint64
+	rawLastID := c.GetHeader("Last-Event-ID")
+	if rawLastID == "" {
+		rawLastID = c.Query("last_event_id")
+	}
+	if rawLastID != "" {
+		if lastID, err = strconv.ParseUint(rawLastID, 10, 64); err != nil {
+			respondInvalidInput(c, "last_event_id", "Invalid Last-Event-ID, expected an update id")
+			return nil, nil, service.RateOptions{}
+		}
+	}
+
+	sub, err := h.Service.Subscribe(pairs, lastID)
+	if err != nil {
+		RespondServiceError(c, err)
+		return nil, nil, service.RateOptions{}
+	}
+	return sub, pairs, service.RateOptions{Precision: precision, Client: clientID(c)}
+}
+
+// StreamEvents serves rate updates for the pairs query parameter as
+// Server-Sent Events.
+func (h *StreamHandler) StreamEvents(c *gin.Context) {
+	sub, pairs, opts := h.openFeed(c, true)
+	if sub == nil {
+		return
+	}
+	defer sub.Close()
+	ctx := c.Request.Context()
+	log.Printf("SSE: Streaming %d pairs (resumed=%t)", len(pairs), sub.Resumed)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", h.Heartbeat.Milliseconds()); err != nil {
+		return
+	}
+	for _, update := range h.Service.InitialUpdates(ctx, sub, pairs, opts) {
+		if err := writeEvent(w, update); err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case update, ok := <-sub.Updates():
+			if !ok {
+				// The client reconnects and resumes from its last event.
+				if err := writeErrorEvent(w, "Subscriber fell behind, reconnecting"); err != nil {
+					log.Printf("SSE: Failed to send error event, error: %v", err)
+					return
+				}
+				w.Flush()
+				return
+			}
+			if err := writeEvent(w, h.Service.QuoteUpdate(update, opts)); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, update models.RateUpdateDto) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: rate\ndata: %s\n\n", update.ID, data)
+	return err
+}
+
+func writeErrorEvent(w io.Writer, message string) error {
+	data, err := json.Marshal(struct{ Error string }{message})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	return err
+}
+
+// StreamWebSocket serves rate updates over a WebSocket. The pairs query
+// parameter is optional; clients can add and remove pairs by sending
+// subscribe and unsubscribe requests.
+func (h *StreamHandler) StreamWebSocket(c *gin.Context) {
+	sub, pairs, opts := h.openFeed(c, false)
+	if sub == nil {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WebSocket Error: Upgrade failed, error: %v", err)
+		return
+	}
+	defer conn.Close()
+	log.Printf("WebSocket: Streaming %d pairs (resumed=%t)", len(pairs), sub.Resumed)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	requests := make(chan wsRequest)
+	go h.readRequests(ctx, cancel, conn, requests)
+
+	for _, update := range h.Service.InitialUpdates(ctx, sub, pairs, opts) {
+		if err := writeMessage(conn, wsMessage{Type: "rate", Update: &update}); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout))
+		case req := <-requests:
+			err = h.handleRequest(ctx, conn, sub, req, opts)
+		case update, ok := <-sub.Updates():
+			if !ok {
+				closing := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber fell behind")
+				if err := conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(streamWriteTimeout)); err != nil {
+					log.Printf("WebSocket: Failed to send close message, error: %v", err)
+				}
+				return
+			}
+			dto := h.Service.QuoteUpdate(update, opts)
+			err = writeMessage(conn, wsMessage{Type: "rate", Update: &dto})
+		}
+		if err != nil {
+			log.Printf("WebSocket: Closing feed, error: %v", err)
+			return
+		}
+	}
+}
+
+// readRequests reads client requests until the connection fails or goes
+// quiet for two heartbeats without answering a ping.
+func (h *StreamHandler) readRequests(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, requests chan<- wsRequest) {
+	defer cancel()
+	conn.SetReadLimit(maxStreamRequestBytes)
+	extend := func() error {
+		return conn.SetReadDeadline(time.Now().Add(2 * h.Heartbeat))
+	}
+	extend()
+	conn.SetPongHandler(func(string) error { return extend() })
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		extend()
+		var req wsRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			// A malformed request is answered, not fatal.
+			req.err = fmt.Errorf("invalid request, expected {\"action\":\"subscribe\",\"pairs\":[\"BASE_TARGET\"]}")
+		}
+		select {
+		case requests <- req:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *StreamHandler) handleRequest(ctx context.Context, conn *websocket.Conn, sub *service.Subscription, req wsRequest, opts service.RateOptions) error {
+	pairs, err := parsePairs(req.Pairs...)
+	if err == nil && len(pairs) == 0 {
+		err = fmt.Errorf("missing pairs")
+	}
+	if req.err != nil {
+		err = req.err
+	}
+	if err != nil {
+		return writeMessage(conn, wsMessage{Type: "error", Error: err.Error(), Code: string(service.ErrInvalidInput)})
+	}
+
+	switch req.Action {
+	case "subscribe":
+		id, err := h.Service.AddPairs(sub, pairs)
+		if err != nil {
+			return writeMessage(conn, wsMessage{Type: "error", Error: err.Error(), Code: string(service.KindOf(err))})
+		}
+		if err := writeMessage(conn, wsMessage{Type: "subscribed", Pairs: req.Pairs}); err != nil {
+			return err
+		}
+		for _, update := range h.Service.Snapshot(ctx, pairs, id, opts) {
+			if err := writeMessage(conn, wsMessage{Type: "rate", Update: &update}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "unsubscribe":
+		h.Service.RemovePairs(sub, pairs)
+		return writeMessage(conn, wsMessage{Type: "unsubscribed", Pairs: req.Pairs})
+	default:
+		return writeMessage(conn, wsMessage{Type: "error", Error: fmt.Sprintf("unknown action %q, expected subscribe or unsubscribe", req.Action), Code: string(service.ErrInvalidInput)})
+	}
+}
+
+func writeMessage(conn *websocket.Conn, msg wsMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return conn.WriteJSON(msg)
+}
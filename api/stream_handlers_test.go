@@ -0,0 +1,324 @@
+package api
+
+import (
+	"assignment1/cache"
+	"assignment1/currency"
+	"assignment1/models"
+	"assignment1/service"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func eurRate(rate string) models.Rate {
+	return models.Rate{Base: "USD", Target: "EUR", Rate: decimal.RequireFromString(rate), Provider: "openexchange", UpdatedAt: time.Now().Unix()}
+}
+
+// streamServer serves both feeds over a service holding a cached USD_EUR
+// rate of 0.8.
+func streamServer(t *testing.T, cfg StreamConfig) (*httptest.Server, *service.RateService) {
+	t.Helper()
+	rs := &service.RateService{
+		Cache:              cache.NewInMemoryCache(),
+		Expiry:             time.Hour,
+		GlobalBaseCurrency: "USD",
+		Currencies:         currency.Default(),
+		Updates:            service.NewRateHub(0),
+	}
+	rs.Cache.Set(context.Background(), "USD_EUR", eurRate("0.8"), time.Hour)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStreamHandler(rs, cfg)
+	r.GET("/rates/stream", h.StreamEvents)
+	r.GET("/rates/ws", h.StreamWebSocket)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, rs
+}
+
+// sseEvent is one block of an event stream, up to its blank line.
+type sseEvent struct {
+	fields map[string]string
+	// comment is set for comment-only blocks such as heartbeats.
+	comment string
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	event := sseEvent{fields: make(map[string]string)}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return event
+		}
+		if comment, ok := strings.CutPrefix(line, ":"); ok {
+			event.comment = strings.TrimSpace(comment)
+			continue
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		event.fields[name] = value
+	}
+}
+
+// readRate returns the next rate event, skipping heartbeats.
+func readRate(t *testing.T, r *bufio.Reader) (uint64, models.RateUpdateDto) {
+	t.Helper()
+	for {
+		event := readEvent(t, r)
+		if event.comment == "heartbeat" {
+			continue
+		}
+		if event.fields["event"] != "rate" {
+			t.Fatalf("event %v, want a rate event", event.fields)
+		}
+		id, err := strconv.ParseUint(event.fields["id"], 10, 64)
+		if err != nil {
+			t.Fatalf("rate event id %q: %v", event.fields["id"], err)
+		}
+		var update models.RateUpdateDto
+		if err := json.Unmarshal([]byte(event.fields["data"]), &update); err != nil {
+			t.Fatalf("rate event data %q: %v", event.fields["data"], err)
+		}
+		if update.ID != id {
+			t.Fatalf("rate event id %d carries update %d", id, update.ID)
+		}
+		return id, update
+	}
+}
+
+func openStream(t *testing.T, url string, header http.Header) *bufio.Reader {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("opening stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("stream responded %d with %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func TestStreamEventsFraming(t *testing.T) {
+	server, rs := streamServer(t, StreamConfig{Heartbeat: 50 * time.Millisecond})
+	r := openStream(t, server.URL+"/rates/stream?pairs=usd_eur&precision=raw", nil)
+
+	if retry := readEvent(t, r).fields["retry"]; retry != "50" {
+		t.Fatalf("retry = %q, want the heartbeat of 50ms", retry)
+	}
+	snapshotID, snapshot := readRate(t, r)
+	if !snapshot.Snapshot || snapshot.Rate.Base != "USD" || snapshot.Rate.Target != "EUR" || !snapshot.Rate.Rate.Equal(decimal.RequireFromString("0.8")) {
+		t.Fatalf("first event = %+v, want the USD_EUR 0.8 snapshot", snapshot)
+	}
+
+	// Unchanged from the snapshot, so nothing is sent for it.
+	rs.Updates.Publish([]models.Rate{eurRate("0.8"), eurRate("0.9")})
+	id, update := readRate(t, r)
+	if id != snapshotID+1 || update.Snapshot || !update.Rate.Rate.Equal(decimal.RequireFromString("0.9")) {
+		t.Errorf("live event %d = %+v, want update %d with 0.9", id, update, snapshotID+1)
+	}
+	if update.PreviousRate == nil || !update.PreviousRate.Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("live event previous rate = %v, want 0.8", update.PreviousRate)
+	}
+
+	if event := readEvent(t, r); event.comment != "heartbeat" {
+		t.Errorf("idle stream sent %+v, want a heartbeat", event)
+	}
+}
+
+func TestStreamEventsResumes(t *testing.T) {
+	server, rs := streamServer(t, StreamConfig{})
+	// A subscriber keeps the pair tracked while the updates are published.
+	first := rs.Updates.Subscribe([]models.RatePair{{Base: "USD", Target: "EUR"}}, 0)
+	defer first.Close()
+	rs.Updates.Publish([]models.Rate{eurRate("0.85")})
+	seen := (<-first.Updates()).ID
+	rs.Updates.Publish([]models.Rate{eurRate("0.9")})
+
+	tests := []struct {
+		name   string
+		query  string
+		header http.Header
+	}{
+		{"Last-Event-ID header", "", http.Header{"Last-Event-ID": {fmt.Sprint(seen)}}},
+		{"last_event_id parameter", fmt.Sprintf("&last_event_id=%d", seen), nil},
+		{"header wins over parameter", "&last_event_id=1", http.Header{"Last-Event-ID": {fmt.Sprint(seen)}}},
+	}
+	for _, tt := range tests {
+		r := openStream(t, server.URL+"/rates/stream?pairs=USD_EUR"+tt.query, tt.header)
+		readEvent(t, r)
+		id, update := readRate(t, r)
+		if id != seen+1 || update.Snapshot || !update.Rate.Rate.Equal(decimal.RequireFromString("0.9")) {
+			t.Errorf("%s: first event %d = %+v, want the missed update %d", tt.name, id, update, seen+1)
+		}
+	}
+
+	// An ID the hub cannot resume from falls back to a snapshot.
+	r := openStream(t, server.URL+"/rates/stream?pairs=USD_EUR&last_event_id=1", nil)
+	readEvent(t, r)
+	if _, update := readRate(t, r); !update.Snapshot {
+		t.Errorf("first event after an unknown ID = %+v, want a snapshot", update)
+	}
+}
+
+func TestWriteErrorEvent(t *testing.T) {
+	var b strings.Builder
+	if err := writeErrorEvent(&b, `Subscriber "fell" behind`); err != nil {
+		t.Fatalf("writeErrorEvent returned error: %v", err)
+	}
+	if want := "event: error\ndata: {\"Error\":\"Subscriber \\\"fell\\\" behind\"}\n\n"; b.String() != want {
+		t.Errorf("error event = %q, want %q", b.String(), want)
+	}
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server, query string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/rates/ws" + query
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, resp, err
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	return msg
+}
+
+func TestStreamWebSocketRequests(t *testing.T) {
+	server, rs := streamServer(t, StreamConfig{})
+	conn, _, err := dialWebSocket(t, server, "", nil)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	send := func(request string) {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(request)); err != nil {
+			t.Fatalf("sending %s: %v", request, err)
+		}
+	}
+
+	send(`{"action":"subscribe","pairs":["usd_eur"]}`)
+	if msg := readMessage(t, conn); msg.Type != "subscribed" || len(msg.Pairs) != 1 || msg.Pairs[0] != "usd_eur" {
+		t.Fatalf("subscribe reply = %+v", msg)
+	}
+	if msg := readMessage(t, conn); msg.Type != "rate" || msg.Update == nil || !msg.Update.Snapshot || msg.Update.Rate.Target != "EUR" {
+		t.Fatalf("message after subscribing = %+v, want a USD_EUR snapshot", msg)
+	}
+
+	rs.Updates.Publish([]models.Rate{eurRate("0.9")})
+	if msg := readMessage(t, conn); msg.Type != "rate" || msg.Update == nil || msg.Update.Snapshot || !msg.Update.Rate.Rate.Equal(decimal.RequireFromString("0.9")) {
+		t.Fatalf("message after publishing = %+v, want the 0.9 update", msg)
+	}
+
+	send(`{"action":"unsubscribe","pairs":["USD_EUR"]}`)
+	if msg := readMessage(t, conn); msg.Type != "unsubscribed" || len(msg.Pairs) != 1 {
+		t.Fatalf("unsubscribe reply = %+v", msg)
+	}
+	// No longer subscribed, so the next message answers the next request.
+	rs.Updates.Publish([]models.Rate{eurRate("0.95")})
+
+	tests := []struct {
+		request string
+		code    string
+	}{
+		{`{"action":`, "INVALID_INPUT"},
+		{`{"action":"dance","pairs":["USD_EUR"]}`, "INVALID_INPUT"},
+		{`{"action":"subscribe","pairs":["USDEUR"]}`, "INVALID_INPUT"},
+		{`{"action":"subscribe"}`, "INVALID_INPUT"},
+		{`{"action":"subscribe","pairs":["USD_QQQ"]}`, "UNKNOWN_CURRENCY"},
+	}
+	for _, tt := range tests {
+		send(tt.request)
+		if msg := readMessage(t, conn); msg.Type != "error" || msg.Code != tt.code || msg.Error == "" {
+			t.Errorf("reply to %s = %+v, want a %s error", tt.request, msg, tt.code)
+		}
+	}
+}
+
+func TestStreamWebSocketPings(t *testing.T) {
+	server, _ := streamServer(t, StreamConfig{Heartbeat: 20 * time.Millisecond})
+	conn, _, err := dialWebSocket(t, server, "?pairs=USD_EUR", nil)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(string) error {
+		pings <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, nil, time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-pings:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d pings, want a ping every heartbeat", i)
+		}
+	}
+}
+
+func TestStreamWebSocketCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		ok      bool
+	}{
+		{"same origin by default", nil, "", true},
+		{"cross origin refused by default", nil, "https://evil.example", false},
+		{"listed origin", []string{"https://app.example"}, "https://app.example", true},
+		{"unlisted origin", []string{"https://app.example"}, "https://evil.example", false},
+		{"any origin", []string{"*"}, "https://evil.example", true},
+	}
+	for _, tt := range tests {
+		server, _ := streamServer(t, StreamConfig{AllowedOrigins: tt.allowed})
+		origin := tt.origin
+		if origin == "" {
+			origin = server.URL
+		}
+		_, resp, err := dialWebSocket(t, server, "?pairs=USD_EUR", http.Header{"Origin": {origin}})
+		if tt.ok && err != nil {
+			t.Errorf("%s: dialing from %s failed: %v", tt.name, origin, err)
+		}
+		if !tt.ok && (err == nil || resp == nil || resp.StatusCode != http.StatusForbidden) {
+			t.Errorf("%s: dialing from %s = %v, want 403", tt.name, origin, err)
+		}
+	}
+}
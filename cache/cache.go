@@ -2,12 +2,19 @@ package cache
 
 import (
 	"assignment1/models"
+	"context"
 	"time"
 )
 
+// RateCache stores rates by key. The context bounds any network round trip;
+// in-process implementations may ignore it.
 type RateCache interface {
-	Get(key string, expiry time.Duration) (models.Rate, bool)
-	Set(key string, data models.Rate, expiry time.Duration)
+	Get(ctx context.Context, key string, expiry time.Duration) (models.Rate, bool)
+	Set(ctx context.Context, key string, data models.Rate, expiry time.Duration)
+	Delete(ctx context.Context, keys ...string)
+	// GetMany looks up several keys in one round trip. Missing or expired
+	// keys are absent from the returned map.
+	GetMany(ctx context.Context, keys []string, expiry time.Duration) map[string]models.Rate
 }
 
 /*type CachedPrice struct {
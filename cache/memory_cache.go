@@ -2,6 +2,7 @@ package cache
 
 import (
 	"assignment1/models"
+	"context"
 	"sync"
 	"time"
 )
@@ -22,7 +23,7 @@ func NewInMemoryCache() *InMemoryCache {
 	}
 }
 
-func (c *InMemoryCache) Get(key string, expiry time.Duration) (models.Rate, bool) {
+func (c *InMemoryCache) Get(_ context.Context, key string, expiry time.Duration) (models.Rate, bool) {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	item, found := c.cache[key]
@@ -32,7 +33,7 @@ func (c *InMemoryCache) Get(key string, expiry time.Duration) (models.Rate, bool
 	return item.Data, true
 }
 
-func (c *InMemoryCache) Set(key string, data models.Rate, expiry time.Duration) {
+func (c *InMemoryCache) Set(_ context.Context, key string, data models.Rate, expiry time.Duration) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	c.cache[key] = CachedPrice{
@@ -40,3 +41,26 @@ func (c *InMemoryCache) Set(key string, data models.Rate, expiry time.Duration)
 		Timestamp: time.Now().Unix(),
 	}
 }
+
+func (c *InMemoryCache) Delete(_ context.Context, keys ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, key := range keys {
+		delete(c.cache, key)
+	}
+}
+
+func (c *InMemoryCache) GetMany(_ context.Context, keys []string, expiry time.Duration) map[string]models.Rate {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	now := time.Now().Unix()
+	found := make(map[string]models.Rate, len(keys))
+	for _, key := range keys {
+		item, ok := c.cache[key]
+		if !ok || now-item.Timestamp > int64(expiry.Seconds()) {
+			continue
+		}
+		found[key] = item.Data
+	}
+	return found
+}
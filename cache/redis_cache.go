@@ -10,7 +10,6 @@ import (
 
 type RedisCache struct {
 	client *redis.Client
-	ctx    context.Context
 }
 
 func NewRedisCache(addr, password string, db int) *RedisCache {
@@ -20,12 +19,11 @@ func NewRedisCache(addr, password string, db int) *RedisCache {
 			Password: password,
 			DB:       db,
 		}),
-		ctx: context.Background(),
 	}
 }
 
-func (r *RedisCache) Get(key string, expiry time.Duration) (models.Rate, bool) {
-	val, err := r.client.Get(r.ctx, key).Result()
+func (r *RedisCache) Get(ctx context.Context, key string, expiry time.Duration) (models.Rate, bool) {
+	val, err := r.client.Get(ctx, key).Result()
 	if err != nil {
 		return models.Rate{}, false
 	}
@@ -40,7 +38,37 @@ func (r *RedisCache) Get(key string, expiry time.Duration) (models.Rate, bool) {
 	return rate, true
 }
 
-func (r *RedisCache) Set(key string, data models.Rate, expiry time.Duration) {
+func (r *RedisCache) Set(ctx context.Context, key string, data models.Rate, expiry time.Duration) {
 	b, _ := json.Marshal(data)
-	r.client.Set(r.ctx, key, b, expiry)
+	r.client.Set(ctx, key, b, expiry)
+}
+
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	r.client.Del(ctx, keys...)
+}
+
+func (r *RedisCache) GetMany(ctx context.Context, keys []string, expiry time.Duration) map[string]models.Rate {
+	found := make(map[string]models.Rate, len(keys))
+	if len(keys) == 0 {
+		return found
+	}
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return found
+	}
+	for i, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		var rate models.Rate
+		if err := json.Unmarshal([]byte(str), &rate); err != nil {
+			continue
+		}
+		found[keys[i]] = rate
+	}
+	return found
 }
@@ -5,21 +5,77 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Port                string
-	CacheExpiry         int
-	ExchangeURL         string
-	ExchangeAppId       string
-	DBUrl               string
-	RedisAddr           string
-	RedisPassword       string
-	RedisDB             int
-	BackgroundTaskTimer time.Duration
-	GlobalBaseCurrency  string
-	GRPCPort            string
+	Port                  string
+	CacheExpiry           int
+	ExchangeURL           string
+	ExchangeAppId         string
+	DBUrl                 string
+	RedisAddr             string
+	RedisPassword         string
+	RedisDB               int
+	BackgroundTaskTimer   time.Duration
+	GlobalBaseCurrency    string
+	GRPCPort              string
+	RoundingMode          string
+	RateSignificantDigits int
+	Providers             []string
+	// ProviderChecks maps provider names to the completeness their results
+	// must meet in failover mode.
+	ProviderChecks        map[string]ProviderCheck
+	ProviderMode          string
+	ConsensusMethod       string
+	ConsensusMaxDeviation float64
+	ConsensusTrimFraction float64
+	ConsensusMinProviders int
+	ECBURL                string
+	ECBHistory            string
+	RatesFile             string
+	RatesFilePollSeconds  int
+	AdminToken            string
+	SpreadsFile           string
+	RateMaxAgeSeconds     int
+	// RateMaxAgePerCurrency maps currency codes to a max age in seconds.
+	RateMaxAgePerCurrency map[string]int
+	ProviderHTTP          ProviderHTTPConfig
+	// StreamHeartbeatSeconds is the heartbeat interval of the SSE and
+	// WebSocket rate feeds, and StreamAllowedOrigins the origins allowed to
+	// open a WebSocket ("*" for any; empty for same origin only).
+	StreamHeartbeatSeconds int
+	StreamAllowedOrigins   []string
+	// Alert webhooks time out after AlertWebhookTimeoutSeconds and are tried
+	// up to AlertWebhookMaxAttempts times, backing off from
+	// AlertWebhookBackoffMillis. Callbacks may only reach public addresses
+	// unless their host, IP or CIDR range is in AlertWebhookAllowedHosts.
+	AlertWebhookTimeoutSeconds int
+	AlertWebhookMaxAttempts    int
+	AlertWebhookBackoffMillis  int
+	AlertWebhookAllowedHosts   []string
+	// APIKeys maps API keys to the client IDs they authenticate.
+	APIKeys map[string]string
+}
+
+// ProviderCheck is read from PROVIDER_MIN_RATES_<NAME> and
+// PROVIDER_REQUIRED_CURRENCIES_<NAME>, e.g. PROVIDER_MIN_RATES_OPENEXCHANGE.
+type ProviderCheck struct {
+	MinRates           int
+	RequiredCurrencies []string
+}
+
+// ProviderHTTPConfig tunes the HTTP client used by network providers. Zero
+// values use the provider package defaults, except for MaxRetries where 0
+// disables retries and nil (unset) uses the default.
+type ProviderHTTPConfig struct {
+	TimeoutSeconds          int
+	MaxRetries              *int
+	BackoffMillis           int
+	MaxBackoffSeconds       int
+	CircuitFailureThreshold int
+	CircuitOpenSeconds      int
 }
 
 func Load() *Config {
@@ -41,19 +97,149 @@ func Load() *Config {
 
 	cacheExpiry, _ := strconv.Atoi(os.Getenv("CACHE_EXPIRY_SECONDS"))
 
+	rateDigits, _ := strconv.Atoi(os.Getenv("RATE_SIGNIFICANT_DIGITS"))
+
+	consensusMaxDeviation, _ := strconv.ParseFloat(os.Getenv("CONSENSUS_MAX_DEVIATION"), 64)
+
+	consensusTrimFraction, _ := strconv.ParseFloat(os.Getenv("CONSENSUS_TRIM_FRACTION"), 64)
+
+	consensusMinProviders, _ := strconv.Atoi(os.Getenv("CONSENSUS_MIN_PROVIDERS"))
+
+	ratesFilePoll, _ := strconv.Atoi(os.Getenv("RATES_FILE_POLL_SECONDS"))
+
+	httpTimeout, _ := strconv.Atoi(os.Getenv("PROVIDER_HTTP_TIMEOUT_SECONDS"))
+	var httpMaxRetries *int
+	if value := os.Getenv("PROVIDER_HTTP_MAX_RETRIES"); value != "" {
+		if retries, err := strconv.Atoi(value); err == nil {
+			httpMaxRetries = &retries
+		} else {
+			log.Printf("Ignoring invalid PROVIDER_HTTP_MAX_RETRIES %q, expected a number", value)
+		}
+	}
+	httpBackoff, _ := strconv.Atoi(os.Getenv("PROVIDER_HTTP_BACKOFF_MS"))
+	httpMaxBackoff, _ := strconv.Atoi(os.Getenv("PROVIDER_HTTP_MAX_BACKOFF_SECONDS"))
+	circuitFailures, _ := strconv.Atoi(os.Getenv("PROVIDER_CIRCUIT_FAILURE_THRESHOLD"))
+	circuitOpen, _ := strconv.Atoi(os.Getenv("PROVIDER_CIRCUIT_OPEN_SECONDS"))
+
+	rateMaxAge, _ := strconv.Atoi(os.Getenv("RATE_MAX_AGE_SECONDS"))
+
+	streamHeartbeat, _ := strconv.Atoi(os.Getenv("STREAM_HEARTBEAT_SECONDS"))
+
+	webhookTimeout, _ := strconv.Atoi(os.Getenv("ALERT_WEBHOOK_TIMEOUT_SECONDS"))
+	webhookAttempts, _ := strconv.Atoi(os.Getenv("ALERT_WEBHOOK_MAX_ATTEMPTS"))
+	webhookBackoff, _ := strconv.Atoi(os.Getenv("ALERT_WEBHOOK_BACKOFF_MS"))
+
+	providers := splitList(os.Getenv("PROVIDERS"))
+	if len(providers) == 0 {
+		providers = []string{"openexchange"}
+	}
+
 	var config = &Config{
-		Port:                os.Getenv("PORT"),
-		ExchangeURL:         os.Getenv("OPENEXCHANGE_URL"),
-		ExchangeAppId:       os.Getenv("OPENEXCHANGE_APP_ID"),
-		CacheExpiry:         cacheExpiry,
-		DBUrl:               os.Getenv("DATABASE_URL"),
-		RedisAddr:           os.Getenv("REDIS_ADDR"),
-		RedisPassword:       os.Getenv("REDIS_PASSWORD"),
-		RedisDB:             redisDB,
-		BackgroundTaskTimer: time.Duration(taskTimer),
-		GlobalBaseCurrency:  os.Getenv("GLOBAL_BASE_CURRENCY"),
-		GRPCPort:            os.Getenv("GRPC_PORT"),
+		Port:                  os.Getenv("PORT"),
+		ExchangeURL:           os.Getenv("OPENEXCHANGE_URL"),
+		ExchangeAppId:         os.Getenv("OPENEXCHANGE_APP_ID"),
+		CacheExpiry:           cacheExpiry,
+		DBUrl:                 os.Getenv("DATABASE_URL"),
+		RedisAddr:             os.Getenv("REDIS_ADDR"),
+		RedisPassword:         os.Getenv("REDIS_PASSWORD"),
+		RedisDB:               redisDB,
+		BackgroundTaskTimer:   time.Duration(taskTimer),
+		GlobalBaseCurrency:    os.Getenv("GLOBAL_BASE_CURRENCY"),
+		GRPCPort:              os.Getenv("GRPC_PORT"),
+		RoundingMode:          os.Getenv("ROUNDING_MODE"),
+		RateSignificantDigits: rateDigits,
+		Providers:             providers,
+		ProviderChecks:        providerChecks(providers),
+		ProviderMode:          os.Getenv("PROVIDER_MODE"),
+		ConsensusMethod:       os.Getenv("CONSENSUS_METHOD"),
+		ConsensusMaxDeviation: consensusMaxDeviation,
+		ConsensusTrimFraction: consensusTrimFraction,
+		ConsensusMinProviders: consensusMinProviders,
+		ECBURL:                os.Getenv("ECB_URL"),
+		ECBHistory:            os.Getenv("ECB_HISTORY"),
+		RatesFile:             os.Getenv("RATES_FILE"),
+		RatesFilePollSeconds:  ratesFilePoll,
+		AdminToken:            os.Getenv("ADMIN_TOKEN"),
+		SpreadsFile:           os.Getenv("SPREADS_FILE"),
+		RateMaxAgeSeconds:     rateMaxAge,
+		RateMaxAgePerCurrency: splitSecondsMap(os.Getenv("RATE_MAX_AGE_PER_CURRENCY")),
+		ProviderHTTP: ProviderHTTPConfig{
+			TimeoutSeconds:          httpTimeout,
+			MaxRetries:              httpMaxRetries,
+			BackoffMillis:           httpBackoff,
+			MaxBackoffSeconds:       httpMaxBackoff,
+			CircuitFailureThreshold: circuitFailures,
+			CircuitOpenSeconds:      circuitOpen,
+		},
+		StreamHeartbeatSeconds:     streamHeartbeat,
+		StreamAllowedOrigins:       splitList(os.Getenv("STREAM_ALLOWED_ORIGINS")),
+		AlertWebhookTimeoutSeconds: webhookTimeout,
+		AlertWebhookMaxAttempts:    webhookAttempts,
+		AlertWebhookBackoffMillis:  webhookBackoff,
+		AlertWebhookAllowedHosts:   splitList(os.Getenv("ALERT_WEBHOOK_ALLOWED_HOSTS")),
+		APIKeys:                    splitKeyMap(os.Getenv("API_KEYS")),
 	}
 
 	return config
 }
+
+// providerChecks reads the completeness check of every provider that sets
+// one. Providers without a check are accepted whenever they answer.
+func providerChecks(providers []string) map[string]ProviderCheck {
+	if os.Getenv("PROVIDER_MIN_RATES") != "" || os.Getenv("PROVIDER_REQUIRED_CURRENCIES") != "" {
+		log.Printf("Ignoring PROVIDER_MIN_RATES and PROVIDER_REQUIRED_CURRENCIES, set them per provider, e.g. PROVIDER_MIN_RATES_OPENEXCHANGE")
+	}
+	checks := make(map[string]ProviderCheck)
+	for _, name := range providers {
+		suffix := strings.ToUpper(name)
+		minRates, _ := strconv.Atoi(os.Getenv("PROVIDER_MIN_RATES_" + suffix))
+		required := splitList(os.Getenv("PROVIDER_REQUIRED_CURRENCIES_" + suffix))
+		if minRates > 0 || len(required) > 0 {
+			checks[name] = ProviderCheck{MinRates: minRates, RequiredCurrencies: required}
+		}
+	}
+	return checks
+}
+
+// splitList parses a comma separated env value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// splitKeyMap parses "KEY:CLIENT,KEY:CLIENT" into key -> client, skipping
+// malformed entries.
+func splitKeyMap(value string) map[string]string {
+	values := make(map[string]string)
+	for _, item := range splitList(value) {
+		key, client, ok := strings.Cut(item, ":")
+		key, client = strings.TrimSpace(key), strings.TrimSpace(client)
+		if !ok || key == "" || client == "" {
+			log.Printf("Ignoring invalid API key entry, expected KEY:CLIENT")
+			continue
+		}
+		values[key] = client
+	}
+	return values
+}
+
+// splitSecondsMap parses "ARS:600,BTC:300" into code -> seconds, skipping
+// malformed entries.
+func splitSecondsMap(value string) map[string]int {
+	values := make(map[string]int)
+	for _, item := range splitList(value) {
+		code, raw, ok := strings.Cut(item, ":")
+		secs, err := strconv.Atoi(strings.TrimSpace(raw))
+		if !ok || err != nil || secs < 0 {
+			log.Printf("Ignoring invalid max age entry %q, expected CODE:SECONDS", item)
+			continue
+		}
+		values[strings.ToUpper(strings.TrimSpace(code))] = secs
+	}
+	return values
+}
@@ -0,0 +1,28 @@
+package currency
+
+const (
+	defaultMinorUnits = 2
+	// unspecifiedMinorUnits is used for catalog entries without an ISO minor
+	// unit (precious metals, SDR, ...), which are quoted in fractions.
+	unspecifiedMinorUnits = 6
+)
+
+// MinorUnits returns the number of decimal places used for amounts in the
+// given currency. Codes missing from the catalog use two decimal places.
+func MinorUnits(code string) int {
+	c, ok := Default().Get(code)
+	if !ok {
+		return defaultMinorUnits
+	}
+	if c.MinorUnits == nil {
+		return unspecifiedMinorUnits
+	}
+	return *c.MinorUnits
+}
+
+// RateDigits returns the significant digits override for rates quoted in
+// code, or 0 when the policy default applies.
+func RateDigits(code string) int32 {
+	c, _ := Default().Get(code)
+	return c.RateDigits
+}
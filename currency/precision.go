@@ -0,0 +1,63 @@
+package currency
+
+import (
+	"assignment1/utility"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"strconv"
+)
+
+const (
+	DefaultRateSignificantDigits = 6
+	maxSignificantDigits         = 30
+)
+
+// Precision is a caller's request for how a rate should be rounded. The zero
+// value means "use the policy".
+type Precision struct {
+	Raw               bool
+	SignificantDigits int32
+}
+
+// ParsePrecision accepts "raw" for unrounded values or a number of
+// significant digits. An empty value defers to the policy.
+func ParsePrecision(value string) (Precision, error) {
+	if value == "" {
+		return Precision{}, nil
+	}
+	if value == "raw" {
+		return Precision{Raw: true}, nil
+	}
+	digits, err := strconv.Atoi(value)
+	if err != nil || digits < 1 || digits > maxSignificantDigits {
+		return Precision{}, fmt.Errorf("invalid precision %q, expected raw or 1-%d significant digits", value, maxSignificantDigits)
+	}
+	return Precision{SignificantDigits: int32(digits)}, nil
+}
+
+// PrecisionPolicy rounds rates to significant digits, so tiny and huge rates
+// keep the same relative accuracy, and amounts to their currency's minor units.
+type PrecisionPolicy struct {
+	RateSignificantDigits int32
+}
+
+func (p PrecisionPolicy) RoundRate(target string, rate decimal.Decimal, requested Precision) decimal.Decimal {
+	if requested.Raw {
+		return rate
+	}
+	digits := requested.SignificantDigits
+	if digits == 0 {
+		digits = RateDigits(target)
+	}
+	if digits == 0 {
+		digits = p.RateSignificantDigits
+	}
+	if digits == 0 {
+		digits = DefaultRateSignificantDigits
+	}
+	return utility.RoundSignificant(rate, digits)
+}
+
+func (p PrecisionPolicy) RoundAmount(code string, amount decimal.Decimal, mode utility.RoundingMode) decimal.Decimal {
+	return utility.RoundWithMode(amount, int32(MinorUnits(code)), mode)
+}
@@ -0,0 +1,91 @@
+package currency
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed iso4217.json
+var iso4217Data []byte
+
+// Currency is one entry of the ISO 4217 catalog. A nil MinorUnits means the
+// standard does not define one (e.g. XAU, XDR). Entries with ISO set to false
+// are widely quoted non-ISO codes such as BTC or CNH.
+type Currency struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	NumericCode string   `json:"numeric_code"`
+	MinorUnits  *int     `json:"minor_units"`
+	Symbol      string   `json:"symbol"`
+	Countries   []string `json:"countries"`
+	Active      bool     `json:"active"`
+	ISO         bool     `json:"iso"`
+	// RateDigits overrides the precision policy's significant digits for
+	// rates quoted in this currency; 0 keeps the policy default.
+	RateDigits int32 `json:"rate_digits"`
+}
+
+type Registry struct {
+	currencies map[string]Currency
+	codes      []string
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryOnce sync.Once
+)
+
+// Default returns the registry seeded from the embedded ISO 4217 dataset.
+func Default() *Registry {
+	defaultRegistryOnce.Do(func() {
+		registry, err := NewRegistry(iso4217Data)
+		if err != nil {
+			log.Fatalf("Failed to load embedded currency catalog : %v", err)
+		}
+		defaultRegistry = registry
+	})
+	return defaultRegistry
+}
+
+func NewRegistry(data []byte) (*Registry, error) {
+	var entries []Currency
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid currency catalog: %w", err)
+	}
+
+	r := &Registry{currencies: make(map[string]Currency, len(entries))}
+	for _, entry := range entries {
+		entry.Code = strings.ToUpper(entry.Code)
+		if _, exists := r.currencies[entry.Code]; exists {
+			return nil, fmt.Errorf("duplicate currency code %s in catalog", entry.Code)
+		}
+		r.currencies[entry.Code] = entry
+		r.codes = append(r.codes, entry.Code)
+	}
+	sort.Strings(r.codes)
+	return r, nil
+}
+
+func (r *Registry) Get(code string) (Currency, bool) {
+	c, ok := r.currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+func (r *Registry) IsKnown(code string) bool {
+	_, ok := r.Get(code)
+	return ok
+}
+
+// List returns every currency in the catalog ordered by code.
+func (r *Registry) List() []Currency {
+	list := make([]Currency, 0, len(r.codes))
+	for _, code := range r.codes {
+		list = append(list, r.currencies[code])
+	}
+	return list
+}
@@ -0,0 +1,27 @@
+package currency
+
+import "time"
+
+// StalenessPolicy sets how old a rate may be before it is reported stale.
+// MaxAge overrides DefaultMaxAge per currency; a pair uses the stricter limit
+// of its two currencies. Zero means no limit.
+type StalenessPolicy struct {
+	DefaultMaxAge time.Duration
+	MaxAge        map[string]time.Duration
+}
+
+// MaxAgeFor returns the limit for base/target, or 0 when neither currency
+// has one.
+func (p StalenessPolicy) MaxAgeFor(base, target string) time.Duration {
+	var limit time.Duration
+	for _, code := range []string{base, target} {
+		maxAge, ok := p.MaxAge[code]
+		if !ok {
+			maxAge = p.DefaultMaxAge
+		}
+		if maxAge > 0 && (limit == 0 || maxAge < limit) {
+			limit = maxAge
+		}
+	}
+	return limit
+}
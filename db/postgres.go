@@ -19,7 +19,7 @@ func Connect(dsn string) {
 		log.Fatal("Failed to connect to the database : ", err)
 	}
 
-	if err := DB.AutoMigrate(&models.Rate{}); err != nil {
+	if err := DB.AutoMigrate(&models.Rate{}, &models.RateHistory{}, &models.RateOverride{}, &models.RateAlert{}, &models.AlertDelivery{}); err != nil {
 		log.Fatal("Auto migration failed : ", err)
 	}
 }
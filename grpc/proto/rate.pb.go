@@ -22,9 +22,18 @@ const (
 )
 
 type GetRateRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
-	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Base   string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Epoch seconds. When set, the rate effective at that instant is returned
+	// from the rate history instead of the current rate.
+	AsOf int64 `protobuf:"varint,3,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	// "raw" for the unrounded rate or a number of significant digits. Defaults
+	// to the server's precision policy.
+	Precision string `protobuf:"bytes,4,opt,name=precision,proto3" json:"precision,omitempty"`
+	// When set, a rate older than this many seconds is rejected with an error
+	// instead of being returned.
+	MaxAgeSeconds int64 `protobuf:"varint,5,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,13 +82,52 @@ func (x *GetRateRequest) GetTarget() string {
 	return ""
 }
 
+func (x *GetRateRequest) GetAsOf() int64 {
+	if x != nil {
+		return x.AsOf
+	}
+	return 0
+}
+
+func (x *GetRateRequest) GetPrecision() string {
+	if x != nil {
+		return x.Precision
+	}
+	return ""
+}
+
+func (x *GetRateRequest) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
 type GetRateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
-	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
-	Rate          float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Base   string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Deprecated: lossy approximation of rate_decimal.
+	//
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Rate      float64 `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	UpdatedAt int64   `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Provider that supplied the rate; "a+b" for cross rates whose legs came
+	// from different providers.
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Currencies the rate was derived through, from base to target, e.g.
+	// ["EUR", "USD", "ARS"].
+	Path []string `protobuf:"bytes,7,rep,name=path,proto3" json:"path,omitempty"`
+	// rate_decimal is the mid-market rate, repeated as mid_decimal; bid and
+	// ask apply the spread configured for the caller (x-api-key metadata).
+	BidDecimal string `protobuf:"bytes,8,opt,name=bid_decimal,json=bidDecimal,proto3" json:"bid_decimal,omitempty"`
+	AskDecimal string `protobuf:"bytes,9,opt,name=ask_decimal,json=askDecimal,proto3" json:"ask_decimal,omitempty"`
+	MidDecimal string `protobuf:"bytes,10,opt,name=mid_decimal,json=midDecimal,proto3" json:"mid_decimal,omitempty"`
+	// Seconds since updated_at; stale is set when that exceeds the server's
+	// staleness policy for the pair.
+	AgeSeconds    int64  `protobuf:"varint,11,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	Stale         bool   `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`
+	RateDecimal   string `protobuf:"bytes,13,opt,name=rate_decimal,json=rateDecimal,proto3" json:"rate_decimal,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -128,6 +176,7 @@ func (x *GetRateResponse) GetTarget() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in proto/rate.proto.
 func (x *GetRateResponse) GetRate() float64 {
 	if x != nil {
 		return x.Rate
@@ -142,30 +191,1318 @@ func (x *GetRateResponse) GetUpdatedAt() int64 {
 	return 0
 }
 
-func (x *GetRateResponse) GetError() string {
+func (x *GetRateResponse) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *GetRateResponse) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *GetRateResponse) GetBidDecimal() string {
+	if x != nil {
+		return x.BidDecimal
+	}
+	return ""
+}
+
+func (x *GetRateResponse) GetAskDecimal() string {
+	if x != nil {
+		return x.AskDecimal
+	}
+	return ""
+}
+
+func (x *GetRateResponse) GetMidDecimal() string {
+	if x != nil {
+		return x.MidDecimal
+	}
+	return ""
+}
+
+func (x *GetRateResponse) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *GetRateResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetRateResponse) GetRateDecimal() string {
+	if x != nil {
+		return x.RateDecimal
+	}
+	return ""
+}
+
+type RatePair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatePair) Reset() {
+	*x = RatePair{}
+	mi := &file_proto_rate_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatePair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatePair) ProtoMessage() {}
+
+func (x *RatePair) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatePair.ProtoReflect.Descriptor instead.
+func (*RatePair) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RatePair) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *RatePair) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type GetRatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pairs []*RatePair            `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Same as GetRateRequest.precision, applied to every pair.
+	Precision     string `protobuf:"bytes,2,opt,name=precision,proto3" json:"precision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesRequest) Reset() {
+	*x = GetRatesRequest{}
+	mi := &file_proto_rate_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesRequest) ProtoMessage() {}
+
+func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetRatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetRatesRequest) GetPairs() []*RatePair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *GetRatesRequest) GetPrecision() string {
+	if x != nil {
+		return x.Precision
+	}
+	return ""
+}
+
+type RateResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Base   string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Empty when error is set.
+	RateDecimal string `protobuf:"bytes,3,opt,name=rate_decimal,json=rateDecimal,proto3" json:"rate_decimal,omitempty"`
+	UpdatedAt   int64  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Error       string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// Provider that supplied the rate; "a+b" for cross rates whose legs came
+	// from different providers.
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Currencies the rate was derived through, from base to target, e.g.
+	// ["EUR", "USD", "ARS"].
+	Path []string `protobuf:"bytes,7,rep,name=path,proto3" json:"path,omitempty"`
+	// As in GetRateResponse.
+	BidDecimal string `protobuf:"bytes,8,opt,name=bid_decimal,json=bidDecimal,proto3" json:"bid_decimal,omitempty"`
+	AskDecimal string `protobuf:"bytes,9,opt,name=ask_decimal,json=askDecimal,proto3" json:"ask_decimal,omitempty"`
+	MidDecimal string `protobuf:"bytes,10,opt,name=mid_decimal,json=midDecimal,proto3" json:"mid_decimal,omitempty"`
+	// Seconds since updated_at; stale is set when that exceeds the server's
+	// staleness policy for the pair.
+	AgeSeconds int64 `protobuf:"varint,11,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	Stale      bool  `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Kind of error, e.g. UNKNOWN_CURRENCY or RATE_UNAVAILABLE.
+	ErrorCode     string `protobuf:"bytes,13,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateResult) Reset() {
+	*x = RateResult{}
+	mi := &file_proto_rate_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateResult) ProtoMessage() {}
+
+func (x *RateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateResult.ProtoReflect.Descriptor instead.
+func (*RateResult) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RateResult) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *RateResult) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *RateResult) GetRateDecimal() string {
+	if x != nil {
+		return x.RateDecimal
+	}
+	return ""
+}
+
+func (x *RateResult) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *RateResult) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-var File_proto_rate_proto protoreflect.FileDescriptor
+func (x *RateResult) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
 
-const file_proto_rate_proto_rawDesc = "" +
-	"\n" +
-	"\x10proto/rate.proto\x12\x04rate\"<\n" +
-	"\x0eGetRateRequest\x12\x12\n" +
-	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
-	"\x06target\x18\x02 \x01(\tR\x06target\"\x86\x01\n" +
-	"\x0fGetRateResponse\x12\x12\n" +
-	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
-	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
-	"\x04rate\x18\x03 \x01(\x01R\x04rate\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x14\n" +
-	"\x05error\x18\x05 \x01(\tR\x05error2G\n" +
+func (x *RateResult) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *RateResult) GetBidDecimal() string {
+	if x != nil {
+		return x.BidDecimal
+	}
+	return ""
+}
+
+func (x *RateResult) GetAskDecimal() string {
+	if x != nil {
+		return x.AskDecimal
+	}
+	return ""
+}
+
+func (x *RateResult) GetMidDecimal() string {
+	if x != nil {
+		return x.MidDecimal
+	}
+	return ""
+}
+
+func (x *RateResult) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *RateResult) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *RateResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type GetRatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested pair, in request order.
+	Results       []*RateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesResponse) Reset() {
+	*x = GetRatesResponse{}
+	mi := &file_proto_rate_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesResponse) ProtoMessage() {}
+
+func (x *GetRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetRatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRatesResponse) GetResults() []*RateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetRateHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Base   string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Epoch seconds, inclusive. to defaults to now.
+	From int64 `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	// "hour" or "day" (default).
+	Interval      string `protobuf:"bytes,5,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateHistoryRequest) Reset() {
+	*x = GetRateHistoryRequest{}
+	mi := &file_proto_rate_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateHistoryRequest) ProtoMessage() {}
+
+func (x *GetRateHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRateHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRateHistoryRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *GetRateHistoryRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *GetRateHistoryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+type RatePoint struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Base   string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	// Bucket start, epoch seconds.
+	Time int64 `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"`
+	// Deprecated: lossy approximation of rate_decimal.
+	//
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Rate          float64 `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	UpdatedAt     int64   `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	RateDecimal   string  `protobuf:"bytes,6,opt,name=rate_decimal,json=rateDecimal,proto3" json:"rate_decimal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatePoint) Reset() {
+	*x = RatePoint{}
+	mi := &file_proto_rate_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatePoint) ProtoMessage() {}
+
+func (x *RatePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatePoint.ProtoReflect.Descriptor instead.
+func (*RatePoint) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RatePoint) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *RatePoint) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *RatePoint) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *RatePoint) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RatePoint) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *RatePoint) GetRateDecimal() string {
+	if x != nil {
+		return x.RateDecimal
+	}
+	return ""
+}
+
+type ConvertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Deprecated: used only when amount_decimal is empty.
+	//
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Amount float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// "half-up", "half-even" or "down". Defaults to the server's ROUNDING_MODE.
+	RoundingMode string `protobuf:"bytes,4,opt,name=rounding_mode,json=roundingMode,proto3" json:"rounding_mode,omitempty"`
+	// e.g. "1250.75".
+	AmountDecimal string `protobuf:"bytes,5,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_proto_rate_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *ConvertRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertRequest) GetRoundingMode() string {
+	if x != nil {
+		return x.RoundingMode
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAmountDecimal() string {
+	if x != nil {
+		return x.AmountDecimal
+	}
+	return ""
+}
+
+type ConvertResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Deprecated: lossy approximations of the *_decimal fields.
+	//
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Amount float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Rate float64 `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	ConvertedAmount float64 `protobuf:"fixed64,5,opt,name=converted_amount,json=convertedAmount,proto3" json:"converted_amount,omitempty"`
+	// Deprecated: Marked as deprecated in proto/rate.proto.
+	Result        float64 `protobuf:"fixed64,6,opt,name=result,proto3" json:"result,omitempty"`
+	MinorUnits    int32   `protobuf:"varint,7,opt,name=minor_units,json=minorUnits,proto3" json:"minor_units,omitempty"`
+	RoundingMode  string  `protobuf:"bytes,8,opt,name=rounding_mode,json=roundingMode,proto3" json:"rounding_mode,omitempty"`
+	UpdatedAt     int64   `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	AmountDecimal string  `protobuf:"bytes,11,opt,name=amount_decimal,json=amountDecimal,proto3" json:"amount_decimal,omitempty"`
+	RateDecimal   string  `protobuf:"bytes,12,opt,name=rate_decimal,json=rateDecimal,proto3" json:"rate_decimal,omitempty"`
+	// Unrounded amount * rate.
+	ConvertedAmountDecimal string `protobuf:"bytes,13,opt,name=converted_amount_decimal,json=convertedAmountDecimal,proto3" json:"converted_amount_decimal,omitempty"`
+	// converted_amount rounded to the target currency's minor units, with
+	// exactly minor_units decimal places.
+	ResultDecimal string `protobuf:"bytes,14,opt,name=result_decimal,json=resultDecimal,proto3" json:"result_decimal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_proto_rate_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConvertResponse) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *ConvertResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *ConvertResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *ConvertResponse) GetConvertedAmount() float64 {
+	if x != nil {
+		return x.ConvertedAmount
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/rate.proto.
+func (x *ConvertResponse) GetResult() float64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetMinorUnits() int32 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetRoundingMode() string {
+	if x != nil {
+		return x.RoundingMode
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetAmountDecimal() string {
+	if x != nil {
+		return x.AmountDecimal
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetRateDecimal() string {
+	if x != nil {
+		return x.RateDecimal
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetConvertedAmountDecimal() string {
+	if x != nil {
+		return x.ConvertedAmountDecimal
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetResultDecimal() string {
+	if x != nil {
+		return x.ResultDecimal
+	}
+	return ""
+}
+
+type ListCurrenciesRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ActiveOnly bool                   `protobuf:"varint,1,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	// Only currencies the service currently holds rates for.
+	SupportedOnly bool `protobuf:"varint,2,opt,name=supported_only,json=supportedOnly,proto3" json:"supported_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesRequest) Reset() {
+	*x = ListCurrenciesRequest{}
+	mi := &file_proto_rate_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesRequest) ProtoMessage() {}
+
+func (x *ListCurrenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesRequest.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListCurrenciesRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+func (x *ListCurrenciesRequest) GetSupportedOnly() bool {
+	if x != nil {
+		return x.SupportedOnly
+	}
+	return false
+}
+
+type Currency struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Code        string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NumericCode string                 `protobuf:"bytes,3,opt,name=numeric_code,json=numericCode,proto3" json:"numeric_code,omitempty"`
+	// Unset when ISO 4217 defines no minor unit (e.g. XAU).
+	MinorUnits    *int32   `protobuf:"varint,4,opt,name=minor_units,json=minorUnits,proto3,oneof" json:"minor_units,omitempty"`
+	Symbol        string   `protobuf:"bytes,5,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Countries     []string `protobuf:"bytes,6,rep,name=countries,proto3" json:"countries,omitempty"`
+	Active        bool     `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	Iso           bool     `protobuf:"varint,8,opt,name=iso,proto3" json:"iso,omitempty"`
+	Supported     bool     `protobuf:"varint,9,opt,name=supported,proto3" json:"supported,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Currency) Reset() {
+	*x = Currency{}
+	mi := &file_proto_rate_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Currency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Currency) ProtoMessage() {}
+
+func (x *Currency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Currency.ProtoReflect.Descriptor instead.
+func (*Currency) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Currency) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Currency) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Currency) GetNumericCode() string {
+	if x != nil {
+		return x.NumericCode
+	}
+	return ""
+}
+
+func (x *Currency) GetMinorUnits() int32 {
+	if x != nil && x.MinorUnits != nil {
+		return *x.MinorUnits
+	}
+	return 0
+}
+
+func (x *Currency) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Currency) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *Currency) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Currency) GetIso() bool {
+	if x != nil {
+		return x.Iso
+	}
+	return false
+}
+
+func (x *Currency) GetSupported() bool {
+	if x != nil {
+		return x.Supported
+	}
+	return false
+}
+
+type ListCurrenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currencies    []*Currency            `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCurrenciesResponse) Reset() {
+	*x = ListCurrenciesResponse{}
+	mi := &file_proto_rate_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesResponse) ProtoMessage() {}
+
+func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListCurrenciesResponse) GetCurrencies() []*Currency {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pairs []*RatePair            `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Same as GetRateRequest.precision, applied to every update.
+	Precision string `protobuf:"bytes,2,opt,name=precision,proto3" json:"precision,omitempty"`
+	// id of the last update received before reconnecting. The stream resumes
+	// with the updates published since then when the server still holds them,
+	// and opens with a snapshot otherwise.
+	LastId        uint64 `protobuf:"varint,3,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_proto_rate_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscribeRequest) GetPairs() []*RatePair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetPrecision() string {
+	if x != nil {
+		return x.Precision
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetLastId() uint64 {
+	if x != nil {
+		return x.LastId
+	}
+	return 0
+}
+
+// The stream opens with one snapshot update per pair holding its current
+// rate, followed by an update whenever a sync changes a subscribed rate.
+type RateUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Increases with every update the server publishes. Snapshots carry the id
+	// they are current as of. Pass the last id seen as last_id to resume.
+	Id     uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Base   string `protobuf:"bytes,2,opt,name=base,proto3" json:"base,omitempty"`
+	Target string `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+	// As in GetRateResponse.
+	RateDecimal string   `protobuf:"bytes,4,opt,name=rate_decimal,json=rateDecimal,proto3" json:"rate_decimal,omitempty"`
+	BidDecimal  string   `protobuf:"bytes,5,opt,name=bid_decimal,json=bidDecimal,proto3" json:"bid_decimal,omitempty"`
+	AskDecimal  string   `protobuf:"bytes,6,opt,name=ask_decimal,json=askDecimal,proto3" json:"ask_decimal,omitempty"`
+	MidDecimal  string   `protobuf:"bytes,7,opt,name=mid_decimal,json=midDecimal,proto3" json:"mid_decimal,omitempty"`
+	Provider    string   `protobuf:"bytes,8,opt,name=provider,proto3" json:"provider,omitempty"`
+	UpdatedAt   int64    `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Path        []string `protobuf:"bytes,10,rep,name=path,proto3" json:"path,omitempty"`
+	// Rate before this update; empty for snapshots and a pair's first update.
+	PreviousRateDecimal string `protobuf:"bytes,11,opt,name=previous_rate_decimal,json=previousRateDecimal,proto3" json:"previous_rate_decimal,omitempty"`
+	Snapshot            bool   `protobuf:"varint,12,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RateUpdate) Reset() {
+	*x = RateUpdate{}
+	mi := &file_proto_rate_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateUpdate) ProtoMessage() {}
+
+func (x *RateUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_rate_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateUpdate.ProtoReflect.Descriptor instead.
+func (*RateUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_rate_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RateUpdate) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RateUpdate) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetRateDecimal() string {
+	if x != nil {
+		return x.RateDecimal
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetBidDecimal() string {
+	if x != nil {
+		return x.BidDecimal
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetAskDecimal() string {
+	if x != nil {
+		return x.AskDecimal
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetMidDecimal() string {
+	if x != nil {
+		return x.MidDecimal
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *RateUpdate) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *RateUpdate) GetPreviousRateDecimal() string {
+	if x != nil {
+		return x.PreviousRateDecimal
+	}
+	return ""
+}
+
+func (x *RateUpdate) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+var File_proto_rate_proto protoreflect.FileDescriptor
+
+const file_proto_rate_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/rate.proto\x12\x04rate\"\x97\x01\n" +
+	"\x0eGetRateRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x13\n" +
+	"\x05as_of\x18\x03 \x01(\x03R\x04asOf\x12\x1c\n" +
+	"\tprecision\x18\x04 \x01(\tR\tprecision\x12&\n" +
+	"\x0fmax_age_seconds\x18\x05 \x01(\x03R\rmaxAgeSeconds\"\xee\x02\n" +
+	"\x0fGetRateResponse\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x16\n" +
+	"\x04rate\x18\x03 \x01(\x01B\x02\x18\x01R\x04rate\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04path\x18\a \x03(\tR\x04path\x12\x1f\n" +
+	"\vbid_decimal\x18\b \x01(\tR\n" +
+	"bidDecimal\x12\x1f\n" +
+	"\vask_decimal\x18\t \x01(\tR\n" +
+	"askDecimal\x12\x1f\n" +
+	"\vmid_decimal\x18\n" +
+	" \x01(\tR\n" +
+	"midDecimal\x12\x1f\n" +
+	"\vage_seconds\x18\v \x01(\x03R\n" +
+	"ageSeconds\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\x12!\n" +
+	"\frate_decimal\x18\r \x01(\tR\vrateDecimalJ\x04\b\x05\x10\x06R\x05error\"6\n" +
+	"\bRatePair\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\"U\n" +
+	"\x0fGetRatesRequest\x12$\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x0e.rate.RatePairR\x05pairs\x12\x1c\n" +
+	"\tprecision\x18\x02 \x01(\tR\tprecision\"\xf9\x02\n" +
+	"\n" +
+	"RateResult\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12!\n" +
+	"\frate_decimal\x18\x03 \x01(\tR\vrateDecimal\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04path\x18\a \x03(\tR\x04path\x12\x1f\n" +
+	"\vbid_decimal\x18\b \x01(\tR\n" +
+	"bidDecimal\x12\x1f\n" +
+	"\vask_decimal\x18\t \x01(\tR\n" +
+	"askDecimal\x12\x1f\n" +
+	"\vmid_decimal\x18\n" +
+	" \x01(\tR\n" +
+	"midDecimal\x12\x1f\n" +
+	"\vage_seconds\x18\v \x01(\x03R\n" +
+	"ageSeconds\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\r \x01(\tR\terrorCode\"K\n" +
+	"\x10GetRatesResponse\x12*\n" +
+	"\aresults\x18\x01 \x03(\v2\x10.rate.RateResultR\aresultsJ\x04\b\x02\x10\x03R\x05error\"\x83\x01\n" +
+	"\x15GetRateHistoryRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x1a\n" +
+	"\binterval\x18\x05 \x01(\tR\binterval\"\xa5\x01\n" +
+	"\tRatePoint\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\x03R\x04time\x12\x16\n" +
+	"\x04rate\x18\x04 \x01(\x01B\x02\x18\x01R\x04rate\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x12!\n" +
+	"\frate_decimal\x18\x06 \x01(\tR\vrateDecimal\"\x9c\x01\n" +
+	"\x0eConvertRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x1a\n" +
+	"\x06amount\x18\x03 \x01(\x01B\x02\x18\x01R\x06amount\x12#\n" +
+	"\rrounding_mode\x18\x04 \x01(\tR\froundingMode\x12%\n" +
+	"\x0eamount_decimal\x18\x05 \x01(\tR\ramountDecimal\"\xd1\x03\n" +
+	"\x0fConvertResponse\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x1a\n" +
+	"\x06amount\x18\x03 \x01(\x01B\x02\x18\x01R\x06amount\x12\x16\n" +
+	"\x04rate\x18\x04 \x01(\x01B\x02\x18\x01R\x04rate\x12-\n" +
+	"\x10converted_amount\x18\x05 \x01(\x01B\x02\x18\x01R\x0fconvertedAmount\x12\x1a\n" +
+	"\x06result\x18\x06 \x01(\x01B\x02\x18\x01R\x06result\x12\x1f\n" +
+	"\vminor_units\x18\a \x01(\x05R\n" +
+	"minorUnits\x12#\n" +
+	"\rrounding_mode\x18\b \x01(\tR\froundingMode\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\x12%\n" +
+	"\x0eamount_decimal\x18\v \x01(\tR\ramountDecimal\x12!\n" +
+	"\frate_decimal\x18\f \x01(\tR\vrateDecimal\x128\n" +
+	"\x18converted_amount_decimal\x18\r \x01(\tR\x16convertedAmountDecimal\x12%\n" +
+	"\x0eresult_decimal\x18\x0e \x01(\tR\rresultDecimalJ\x04\b\n" +
+	"\x10\vR\x05error\"_\n" +
+	"\x15ListCurrenciesRequest\x12\x1f\n" +
+	"\vactive_only\x18\x01 \x01(\bR\n" +
+	"activeOnly\x12%\n" +
+	"\x0esupported_only\x18\x02 \x01(\bR\rsupportedOnly\"\x89\x02\n" +
+	"\bCurrency\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fnumeric_code\x18\x03 \x01(\tR\vnumericCode\x12$\n" +
+	"\vminor_units\x18\x04 \x01(\x05H\x00R\n" +
+	"minorUnits\x88\x01\x01\x12\x16\n" +
+	"\x06symbol\x18\x05 \x01(\tR\x06symbol\x12\x1c\n" +
+	"\tcountries\x18\x06 \x03(\tR\tcountries\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\x12\x10\n" +
+	"\x03iso\x18\b \x01(\bR\x03iso\x12\x1c\n" +
+	"\tsupported\x18\t \x01(\bR\tsupportedB\x0e\n" +
+	"\f_minor_units\"U\n" +
+	"\x16ListCurrenciesResponse\x12.\n" +
+	"\n" +
+	"currencies\x18\x01 \x03(\v2\x0e.rate.CurrencyR\n" +
+	"currenciesJ\x04\b\x02\x10\x03R\x05error\"o\n" +
+	"\x10SubscribeRequest\x12$\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x0e.rate.RatePairR\x05pairs\x12\x1c\n" +
+	"\tprecision\x18\x02 \x01(\tR\tprecision\x12\x17\n" +
+	"\alast_id\x18\x03 \x01(\x04R\x06lastId\"\xed\x02\n" +
+	"\n" +
+	"RateUpdate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04base\x18\x02 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x03 \x01(\tR\x06target\x12!\n" +
+	"\frate_decimal\x18\x04 \x01(\tR\vrateDecimal\x12\x1f\n" +
+	"\vbid_decimal\x18\x05 \x01(\tR\n" +
+	"bidDecimal\x12\x1f\n" +
+	"\vask_decimal\x18\x06 \x01(\tR\n" +
+	"askDecimal\x12\x1f\n" +
+	"\vmid_decimal\x18\a \x01(\tR\n" +
+	"midDecimal\x12\x1a\n" +
+	"\bprovider\x18\b \x01(\tR\bprovider\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAt\x12\x12\n" +
+	"\x04path\x18\n" +
+	" \x03(\tR\x04path\x122\n" +
+	"\x15previous_rate_decimal\x18\v \x01(\tR\x13previousRateDecimal\x12\x1a\n" +
+	"\bsnapshot\x18\f \x01(\bR\bsnapshot2\x91\x03\n" +
 	"\vRateService\x128\n" +
-	"\aGetRate\x12\x14.rate.GetRateRequest\x1a\x15.rate.GetRateResponse\"\x00B\x13Z\x11grpc/proto;ratepbb\x06proto3"
+	"\aGetRate\x12\x14.rate.GetRateRequest\x1a\x15.rate.GetRateResponse\"\x00\x12;\n" +
+	"\bGetRates\x12\x15.rate.GetRatesRequest\x1a\x16.rate.GetRatesResponse\"\x00\x12B\n" +
+	"\x0eGetRateHistory\x12\x1b.rate.GetRateHistoryRequest\x1a\x0f.rate.RatePoint\"\x000\x01\x128\n" +
+	"\aConvert\x12\x14.rate.ConvertRequest\x1a\x15.rate.ConvertResponse\"\x00\x12M\n" +
+	"\x0eListCurrencies\x12\x1b.rate.ListCurrenciesRequest\x1a\x1c.rate.ListCurrenciesResponse\"\x00\x12>\n" +
+	"\x0eSubscribeRates\x12\x16.rate.SubscribeRequest\x1a\x10.rate.RateUpdate\"\x000\x01B\x13Z\x11grpc/proto;ratepbb\x06proto3"
 
 var (
 	file_proto_rate_proto_rawDescOnce sync.Once
@@ -179,19 +1516,46 @@ func file_proto_rate_proto_rawDescGZIP() []byte {
 	return file_proto_rate_proto_rawDescData
 }
 
-var file_proto_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_proto_rate_proto_goTypes = []any{
-	(*GetRateRequest)(nil),  // 0: rate.GetRateRequest
-	(*GetRateResponse)(nil), // 1: rate.GetRateResponse
+	(*GetRateRequest)(nil),         // 0: rate.GetRateRequest
+	(*GetRateResponse)(nil),        // 1: rate.GetRateResponse
+	(*RatePair)(nil),               // 2: rate.RatePair
+	(*GetRatesRequest)(nil),        // 3: rate.GetRatesRequest
+	(*RateResult)(nil),             // 4: rate.RateResult
+	(*GetRatesResponse)(nil),       // 5: rate.GetRatesResponse
+	(*GetRateHistoryRequest)(nil),  // 6: rate.GetRateHistoryRequest
+	(*RatePoint)(nil),              // 7: rate.RatePoint
+	(*ConvertRequest)(nil),         // 8: rate.ConvertRequest
+	(*ConvertResponse)(nil),        // 9: rate.ConvertResponse
+	(*ListCurrenciesRequest)(nil),  // 10: rate.ListCurrenciesRequest
+	(*Currency)(nil),               // 11: rate.Currency
+	(*ListCurrenciesResponse)(nil), // 12: rate.ListCurrenciesResponse
+	(*SubscribeRequest)(nil),       // 13: rate.SubscribeRequest
+	(*RateUpdate)(nil),             // 14: rate.RateUpdate
 }
 var file_proto_rate_proto_depIdxs = []int32{
-	0, // 0: rate.RateService.GetRate:input_type -> rate.GetRateRequest
-	1, // 1: rate.RateService.GetRate:output_type -> rate.GetRateResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2,  // 0: rate.GetRatesRequest.pairs:type_name -> rate.RatePair
+	4,  // 1: rate.GetRatesResponse.results:type_name -> rate.RateResult
+	11, // 2: rate.ListCurrenciesResponse.currencies:type_name -> rate.Currency
+	2,  // 3: rate.SubscribeRequest.pairs:type_name -> rate.RatePair
+	0,  // 4: rate.RateService.GetRate:input_type -> rate.GetRateRequest
+	3,  // 5: rate.RateService.GetRates:input_type -> rate.GetRatesRequest
+	6,  // 6: rate.RateService.GetRateHistory:input_type -> rate.GetRateHistoryRequest
+	8,  // 7: rate.RateService.Convert:input_type -> rate.ConvertRequest
+	10, // 8: rate.RateService.ListCurrencies:input_type -> rate.ListCurrenciesRequest
+	13, // 9: rate.RateService.SubscribeRates:input_type -> rate.SubscribeRequest
+	1,  // 10: rate.RateService.GetRate:output_type -> rate.GetRateResponse
+	5,  // 11: rate.RateService.GetRates:output_type -> rate.GetRatesResponse
+	7,  // 12: rate.RateService.GetRateHistory:output_type -> rate.RatePoint
+	9,  // 13: rate.RateService.Convert:output_type -> rate.ConvertResponse
+	12, // 14: rate.RateService.ListCurrencies:output_type -> rate.ListCurrenciesResponse
+	14, // 15: rate.RateService.SubscribeRates:output_type -> rate.RateUpdate
+	10, // [10:16] is the sub-list for method output_type
+	4,  // [4:10] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_rate_proto_init() }
@@ -199,13 +1563,14 @@ func file_proto_rate_proto_init() {
 	if File_proto_rate_proto != nil {
 		return
 	}
+	file_proto_rate_proto_msgTypes[11].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_rate_proto_rawDesc), len(file_proto_rate_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// AdminAuth requires the admin token as a bearer token in the Authorization
+// header.
+func AdminAuth(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		provided := []byte(c.GetHeader("Authorization"))
+		if subtle.ConstantTimeCompare(provided, expected) != 1 {
+			log.Printf("Admin Auth: Rejected %s %s", c.Request.Method, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Missing or invalid admin token",
+			})
+			return
+		}
+		c.Next()
+	}
+}
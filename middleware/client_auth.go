@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"log"
+	"net/http"
+)
+
+const (
+	// APIKeyHeader carries the API key identifying a REST client.
+	APIKeyHeader = "X-API-Key"
+	// APIKeyMetadata is the gRPC counterpart of APIKeyHeader.
+	APIKeyMetadata = "x-api-key"
+)
+
+type clientContextKey struct{}
+
+// ClientKeys maps API keys to the client IDs they authenticate. Keys are
+// stored hashed so a lookup does not leak them through timing.
+type ClientKeys struct {
+	clients map[[sha256.Size]byte]string
+}
+
+// NewClientKeys builds the key table from API key -> client ID.
+func NewClientKeys(keys map[string]string) *ClientKeys {
+	k := &ClientKeys{clients: make(map[[sha256.Size]byte]string, len(keys))}
+	for key, client := range keys {
+		k.clients[sha256.Sum256([]byte(key))] = client
+	}
+	return k
+}
+
+// Lookup returns the client authenticated by key.
+func (k *ClientKeys) Lookup(key string) (string, bool) {
+	if k == nil || key == "" {
+		return "", false
+	}
+	client, ok := k.clients[sha256.Sum256([]byte(key))]
+	return client, ok
+}
+
+// WithClient returns ctx carrying the authenticated client ID.
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// ClientFromContext returns the client authenticated for the request, or ""
+// for anonymous callers.
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientContextKey{}).(string)
+	return client
+}
+
+// ClientAuth resolves the API key in the X-API-Key header to a client ID and
+// stores it in the request context. Requests without a key are anonymous;
+// requests with an unknown key are rejected.
+func ClientAuth(keys *ClientKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		client, ok := keys.Lookup(key)
+		if !ok {
+			log.Printf("Client Auth: Rejected unknown API key for %s %s", c.Request.Method, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "Invalid API key",
+			})
+			return
+		}
+		c.Request = c.Request.WithContext(WithClient(c.Request.Context(), client))
+		c.Next()
+	}
+}
+
+// GRPCClientAuth returns interceptors resolving the x-api-key metadata key
+// like ClientAuth does for REST.
+func GRPCClientAuth(keys *ClientKeys) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		values := metadata.ValueFromIncomingContext(ctx, APIKeyMetadata)
+		if len(values) == 0 || values[0] == "" {
+			return ctx, nil
+		}
+		client, ok := keys.Lookup(values[0])
+		if !ok {
+			log.Printf("Client Auth: Rejected unknown gRPC API key")
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		return WithClient(ctx, client), nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+	return unary, stream
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func clientRouter(keys *ClientKeys) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ClientAuth(keys))
+	r.GET("/whoami", func(c *gin.Context) {
+		c.String(http.StatusOK, ClientFromContext(c.Request.Context()))
+	})
+	return r
+}
+
+func TestClientAuth(t *testing.T) {
+	r := clientRouter(NewClientKeys(map[string]string{"secret-key": "acme"}))
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		status   int
+		clientID string
+	}{
+		{name: "valid key", headers: map[string]string{APIKeyHeader: "secret-key"}, status: http.StatusOK, clientID: "acme"},
+		{name: "anonymous", status: http.StatusOK, clientID: ""},
+		{name: "unknown key", headers: map[string]string{APIKeyHeader: "guess"}, status: http.StatusUnauthorized},
+		{name: "client id header is ignored", headers: map[string]string{"X-Client-ID": "acme"}, status: http.StatusOK, clientID: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && w.Body.String() != tt.clientID {
+				t.Errorf("client = %q, want %q", w.Body.String(), tt.clientID)
+			}
+		})
+	}
+}
+
+func TestGRPCClientAuth(t *testing.T) {
+	unary, _ := GRPCClientAuth(NewClientKeys(map[string]string{"secret-key": "acme"}))
+	whoami := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ClientFromContext(ctx), nil
+	}
+	call := func(md metadata.MD) (interface{}, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		return unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/rate.RateService/GetRate"}, whoami)
+	}
+
+	if client, err := call(metadata.Pairs(APIKeyMetadata, "secret-key")); err != nil || client != "acme" {
+		t.Errorf("valid key: client = %v, err = %v", client, err)
+	}
+	if client, err := call(metadata.Pairs("x-client-id", "acme")); err != nil || client != "" {
+		t.Errorf("client id metadata: client = %v, err = %v, want anonymous", client, err)
+	}
+	if _, err := call(metadata.Pairs(APIKeyMetadata, "guess")); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown key: err = %v, want Unauthenticated", err)
+	}
+}
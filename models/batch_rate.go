@@ -0,0 +1,17 @@
+package models
+
+type RatePair struct {
+	Base   string
+	Target string
+}
+
+// BatchRateResult is the outcome for one pair of a batch lookup: either Data
+// or Error is set.
+type BatchRateResult struct {
+	Base   string
+	Target string
+	Data   *RateDto `json:",omitempty"`
+	Error  string   `json:",omitempty"`
+	// ErrorCode is the kind of Error, e.g. UNKNOWN_CURRENCY.
+	ErrorCode string `json:",omitempty"`
+}
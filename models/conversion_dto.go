@@ -0,0 +1,17 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+type ConversionDto struct {
+	From            string
+	To              string
+	Amount          decimal.Decimal
+	Rate            decimal.Decimal
+	ConvertedAmount decimal.Decimal
+	// Result is the rounded amount with exactly MinorUnits decimal places,
+	// e.g. "12.50", so REST and gRPC render it the same way.
+	Result       string
+	MinorUnits   int
+	RoundingMode string
+	UpdatedAt    int64
+}
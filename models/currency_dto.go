@@ -0,0 +1,14 @@
+package models
+
+type CurrencyDto struct {
+	Code        string
+	Name        string
+	NumericCode string
+	MinorUnits  *int
+	Symbol      string
+	Countries   []string
+	Active      bool
+	ISO         bool
+	// Supported reports whether the service currently holds rates for it.
+	Supported bool
+}
@@ -0,0 +1,27 @@
+package models
+
+// ProviderStatus reports the recent fetch outcomes of one rate provider. Times
+// are epoch seconds and zero when the event has not happened yet.
+type ProviderStatus struct {
+	Name                string
+	LastAttemptAt       int64
+	LastSuccessAt       int64
+	LastErrorAt         int64  `json:",omitempty"`
+	LastError           string `json:",omitempty"`
+	ConsecutiveFailures int
+	RateCount           int
+	// CircuitState is closed, open or half-open for providers fetching over
+	// HTTP, and empty otherwise.
+	CircuitState string `json:",omitempty"`
+}
+
+// ProviderStatusReport covers the combined provider used by the sync and each
+// configured provider. Stale is set when the sync has not succeeded within
+// two sync intervals.
+type ProviderStatusReport struct {
+	Mode       string
+	LastSyncAt int64
+	Stale      bool
+	Sync       ProviderStatus
+	Providers  []ProviderStatus
+}
@@ -1,9 +1,15 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type Rate struct {
-	ID        uint   `gorm:"primaryKey"`
-	Base      string `gorm:"index:idx_base_target,unique"`
-	Target    string `gorm:"index:idx_base_target,unique"`
-	Rate      float64
+	ID        uint            `gorm:"primaryKey"`
+	Base      string          `gorm:"index:idx_base_target,unique"`
+	Target    string          `gorm:"index:idx_base_target,unique"`
+	Rate      decimal.Decimal `gorm:"type:numeric"`
+	Provider  string
 	UpdatedAt int64
+	// Path lists the currencies a derived rate was chained through, from
+	// Base to Target. It is not stored.
+	Path []string `gorm:"-" json:",omitempty"`
 }
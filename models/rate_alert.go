@@ -0,0 +1,66 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// Alert conditions. Above and below compare the rate with Threshold; change
+// compares the absolute percentage change over Window (seconds) with it.
+const (
+	AlertAbove  = "above"
+	AlertBelow  = "below"
+	AlertChange = "change"
+)
+
+// RateAlert notifies CallbackURL when its condition starts to hold. It fires
+// once per crossing: Triggered stays set until the condition stops holding.
+type RateAlert struct {
+	ID          uint   `gorm:"primaryKey"`
+	ClientID    string `gorm:"index"`
+	Base        string `gorm:"index:idx_alert_pair"`
+	Target      string `gorm:"index:idx_alert_pair"`
+	Condition   string
+	Threshold   decimal.Decimal `gorm:"type:numeric"`
+	Window      int64
+	CallbackURL string
+	// Secret signs the webhooks; it is only returned when the alert is
+	// created.
+	Secret          string `json:"-"`
+	Triggered       bool
+	LastTriggeredAt int64
+	CreatedAt       int64 `gorm:"autoCreateTime"`
+	UpdatedAt       int64 `gorm:"autoUpdateTime"`
+}
+
+// AlertDelivery logs one attempt to deliver an alert webhook.
+type AlertDelivery struct {
+	ID         uint   `gorm:"primaryKey"`
+	AlertID    uint   `gorm:"index"`
+	EventID    string `gorm:"index"`
+	Attempt    int
+	StatusCode int
+	Error      string
+	Success    bool
+	CreatedAt  int64 `gorm:"autoCreateTime"`
+}
+
+// AlertEvent is the webhook body sent when an alert fires. Reference and
+// ChangePercent are set for change alerts.
+type AlertEvent struct {
+	EventID       string
+	AlertID       uint
+	Base          string
+	Target        string
+	Condition     string
+	Threshold     decimal.Decimal
+	Window        int64 `json:",omitempty"`
+	Rate          decimal.Decimal
+	Reference     *decimal.Decimal `json:",omitempty"`
+	ChangePercent *decimal.Decimal `json:",omitempty"`
+	UpdatedAt     int64
+	TriggeredAt   int64
+}
+
+// CreatedAlertDto is returned once on creation, with the signing secret.
+type CreatedAlertDto struct {
+	RateAlert
+	Secret string
+}
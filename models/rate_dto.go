@@ -1,19 +1,41 @@
 package models
 
-import "assignment1/utility"
+import "github.com/shopspring/decimal"
 
+// RateDto is a quoted rate. Rate is the mid-market rate, also returned as
+// Mid; Bid and Ask apply the configured spread around it.
 type RateDto struct {
 	Base      string
 	Target    string
-	Rate      float64
+	Rate      decimal.Decimal
+	Bid       decimal.Decimal
+	Ask       decimal.Decimal
+	Mid       decimal.Decimal
+	Provider  string
 	UpdatedAt int64
+	// Age is the number of seconds since UpdatedAt; Stale is set when it
+	// exceeds the staleness policy for the pair.
+	Age   int64
+	Stale bool
+	// Path is the chain of currencies the rate was derived through; a quote
+	// stored for the pair itself is just [Base, Target].
+	Path []string
 }
 
 func NewRateDto(rate Rate) RateDto {
+	path := rate.Path
+	if len(path) == 0 {
+		path = []string{rate.Base, rate.Target}
+	}
 	return RateDto{
 		Base:      rate.Base,
 		Target:    rate.Target,
-		Rate:      utility.Round(rate.Rate, 2),
+		Rate:      rate.Rate,
+		Bid:       rate.Rate,
+		Ask:       rate.Rate,
+		Mid:       rate.Rate,
+		Provider:  rate.Provider,
 		UpdatedAt: rate.UpdatedAt,
+		Path:      path,
 	}
 }
@@ -0,0 +1,36 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// RateHistory is an append-only record of every rate observed during a sync.
+// Unlike Rate, rows are never updated, so it can answer "what rate did we
+// serve at time T".
+type RateHistory struct {
+	ID         uint            `gorm:"primaryKey"`
+	Base       string          `gorm:"index:idx_history_pair_observed,unique,priority:1"`
+	Target     string          `gorm:"index:idx_history_pair_observed,unique,priority:2"`
+	Provider   string          `gorm:"index:idx_history_pair_observed,unique,priority:3"`
+	ObservedAt int64           `gorm:"index:idx_history_pair_observed,unique,priority:4"`
+	Rate       decimal.Decimal `gorm:"type:numeric"`
+	CreatedAt  int64           `gorm:"autoCreateTime"`
+}
+
+func NewRateHistory(rate Rate) RateHistory {
+	return RateHistory{
+		Base:       rate.Base,
+		Target:     rate.Target,
+		Provider:   rate.Provider,
+		ObservedAt: rate.UpdatedAt,
+		Rate:       rate.Rate,
+	}
+}
+
+func (h RateHistory) ToRate() Rate {
+	return Rate{
+		Base:      h.Base,
+		Target:    h.Target,
+		Rate:      h.Rate,
+		Provider:  h.Provider,
+		UpdatedAt: h.ObservedAt,
+	}
+}
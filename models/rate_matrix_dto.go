@@ -0,0 +1,11 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// RateMatrixDto holds the rate from Base to every supported target.
+// UpdatedAt is the oldest contributing observation.
+type RateMatrixDto struct {
+	Base      string
+	Rates     map[string]decimal.Decimal
+	UpdatedAt int64
+}
@@ -0,0 +1,27 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// RateOverride pins a pair to an operator supplied rate until ExpiresAt
+// (epoch seconds), taking precedence over provider data.
+type RateOverride struct {
+	ID        uint            `gorm:"primaryKey"`
+	Base      string          `gorm:"index:idx_override_base_target,unique"`
+	Target    string          `gorm:"index:idx_override_base_target,unique"`
+	Rate      decimal.Decimal `gorm:"type:numeric"`
+	Reason    string
+	ExpiresAt int64 `gorm:"index"`
+	CreatedAt int64 `gorm:"autoCreateTime"`
+	UpdatedAt int64 `gorm:"autoUpdateTime"`
+}
+
+// ToRate returns the override as a rate observed at updatedAt.
+func (o RateOverride) ToRate(provider string, updatedAt int64) Rate {
+	return Rate{
+		Base:      o.Base,
+		Target:    o.Target,
+		Rate:      o.Rate,
+		Provider:  provider,
+		UpdatedAt: updatedAt,
+	}
+}
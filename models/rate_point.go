@@ -0,0 +1,13 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// RatePoint is one bucket of a rate time series. Time is the bucket start and
+// UpdatedAt the latest observation that contributed to it (both epoch time).
+type RatePoint struct {
+	Base      string
+	Target    string
+	Time      int64
+	Rate      decimal.Decimal
+	UpdatedAt int64
+}
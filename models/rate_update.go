@@ -0,0 +1,13 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// RateUpdateDto is a rate pushed to a subscriber. Snapshot updates carry the
+// current rate when a pair is subscribed; later ones carry a change along
+// with the rate it replaced. ID is the resume point after this update.
+type RateUpdateDto struct {
+	ID           uint64
+	Snapshot     bool
+	PreviousRate *decimal.Decimal `json:",omitempty"`
+	Rate         RateDto
+}
@@ -0,0 +1,158 @@
+package pricing
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+)
+
+var (
+	two  = decimal.NewFromInt(2)
+	half = decimal.RequireFromString("0.5")
+)
+
+// SpreadRules are spreads expressed as a fraction of the mid rate (0.002 is
+// 0.2%) covering the full distance between bid and ask.
+type SpreadRules struct {
+	Default    *decimal.Decimal
+	Currencies map[string]decimal.Decimal
+	Pairs      map[string]decimal.Decimal
+}
+
+// Spreads resolves the spread for a pair and API client. The most specific
+// rule wins: a client's pair, currency and default rules, then the global
+// pair, currency and default rules. A nil *Spreads quotes at mid.
+type Spreads struct {
+	SpreadRules
+	Clients map[string]SpreadRules
+}
+
+// spreadsDocument is the YAML or JSON layout of a spreads file, e.g.
+//
+//	default: "0.002"
+//	currencies: {ARS: "0.02"}
+//	pairs: {USD_JPY: "0.001"}
+//	clients:
+//	  retail-web: {default: "0.01"}
+type spreadsDocument struct {
+	rulesDocument `yaml:",inline"`
+	Clients       map[string]rulesDocument `yaml:"clients"`
+}
+
+type rulesDocument struct {
+	Default    string            `yaml:"default"`
+	Currencies map[string]string `yaml:"currencies"`
+	Pairs      map[string]string `yaml:"pairs"`
+}
+
+// LoadSpreads reads a spreads file. JSON is accepted as it is valid YAML.
+func LoadSpreads(path string) (*Spreads, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var doc spreadsDocument
+	if err := yaml.NewDecoder(file).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse spreads file %s: %w", path, err)
+	}
+
+	rules, err := doc.rulesDocument.parse()
+	if err != nil {
+		return nil, err
+	}
+	spreads := &Spreads{SpreadRules: rules, Clients: make(map[string]SpreadRules, len(doc.Clients))}
+	for client, clientDoc := range doc.Clients {
+		clientRules, err := clientDoc.parse()
+		if err != nil {
+			return nil, fmt.Errorf("client %s: %w", client, err)
+		}
+		spreads.Clients[client] = clientRules
+	}
+	return spreads, nil
+}
+
+func (d rulesDocument) parse() (SpreadRules, error) {
+	rules := SpreadRules{
+		Currencies: make(map[string]decimal.Decimal, len(d.Currencies)),
+		Pairs:      make(map[string]decimal.Decimal, len(d.Pairs)),
+	}
+	if d.Default != "" {
+		spread, err := parseSpread("default", d.Default)
+		if err != nil {
+			return SpreadRules{}, err
+		}
+		rules.Default = &spread
+	}
+	for code, value := range d.Currencies {
+		spread, err := parseSpread(code, value)
+		if err != nil {
+			return SpreadRules{}, err
+		}
+		rules.Currencies[strings.ToUpper(code)] = spread
+	}
+	for pair, value := range d.Pairs {
+		if len(strings.Split(pair, "_")) != 2 {
+			return SpreadRules{}, fmt.Errorf("invalid pair %q, expected BASE_TARGET", pair)
+		}
+		spread, err := parseSpread(pair, value)
+		if err != nil {
+			return SpreadRules{}, err
+		}
+		rules.Pairs[strings.ToUpper(pair)] = spread
+	}
+	return rules, nil
+}
+
+func parseSpread(name, value string) (decimal.Decimal, error) {
+	spread, err := decimal.NewFromString(value)
+	if err != nil || spread.IsNegative() || spread.GreaterThanOrEqual(two) {
+		return decimal.Zero, fmt.Errorf("invalid spread %q for %s, expected a fraction between 0 and 2", value, name)
+	}
+	return spread, nil
+}
+
+// Spread returns the spread for base/target quoted to client.
+func (s *Spreads) Spread(client, base, target string) decimal.Decimal {
+	if s == nil {
+		return decimal.Zero
+	}
+	if rules, ok := s.Clients[client]; ok && client != "" {
+		if spread, ok := rules.lookup(base, target); ok {
+			return spread
+		}
+	}
+	if spread, ok := s.SpreadRules.lookup(base, target); ok {
+		return spread
+	}
+	return decimal.Zero
+}
+
+// lookup applies the pair rule (in either direction), then the larger of the
+// two currency rules, then the default.
+func (r SpreadRules) lookup(base, target string) (decimal.Decimal, bool) {
+	if spread, ok := r.Pairs[base+"_"+target]; ok {
+		return spread, true
+	}
+	if spread, ok := r.Pairs[target+"_"+base]; ok {
+		return spread, true
+	}
+	baseSpread, baseOk := r.Currencies[base]
+	targetSpread, targetOk := r.Currencies[target]
+	if baseOk || targetOk {
+		return decimal.Max(baseSpread, targetSpread), true
+	}
+	if r.Default != nil {
+		return *r.Default, true
+	}
+	return decimal.Zero, false
+}
+
+// Quote splits spread evenly around mid.
+func Quote(mid, spread decimal.Decimal) (bid, ask decimal.Decimal) {
+	offset := mid.Mul(spread).Mul(half)
+	return mid.Sub(offset), mid.Add(offset)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	b := &CircuitBreaker{Name: "test", FailureThreshold: 2, OpenTimeout: 20 * time.Millisecond}
+
+	// A success in between resets the count.
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("state %s after non-consecutive failures, want closed", state)
+	}
+
+	b.Failure()
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("state %s after %d consecutive failures, want open", state, b.ConsecutiveFailures())
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow on an open circuit = %v, want ErrCircuitOpen", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("state %s after the open timeout, want half-open", state)
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow for the trial call = %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second Allow during the trial = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed trial re-opens the circuit for another timeout.
+	b.Failure()
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("state %s after a failed trial, want open", state)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow after the second timeout = %v", err)
+	}
+	b.Success()
+	if state, failures := b.State(), b.ConsecutiveFailures(); state != CircuitClosed || failures != 0 {
+		t.Errorf("state %s with %d failures after a successful trial, want closed with 0", state, failures)
+	}
+}
+
+func TestCircuitBreakerAbandonReleasesTrial(t *testing.T) {
+	b := &CircuitBreaker{Name: "test", FailureThreshold: 1, OpenTimeout: time.Millisecond}
+	b.Failure()
+	time.Sleep(2 * time.Millisecond)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow for the trial call = %v", err)
+	}
+	b.Abandon()
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Errorf("state %s after an abandoned trial, want half-open", state)
+	}
+	if err := b.Allow(); err != nil {
+		t.Errorf("Allow after an abandoned trial = %v, want a new trial", err)
+	}
+	if failures := b.ConsecutiveFailures(); failures != 1 {
+		t.Errorf("%d failures, want the abandoned trial not counted", failures)
+	}
+}
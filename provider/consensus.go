@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ConsensusProviderName = "consensus"
+
+	ConsensusMedian      = "median"
+	ConsensusTrimmedMean = "trimmed-mean"
+)
+
+// ConsensusProvider fetches from all Providers concurrently and publishes one
+// aggregated rate per pair. Quotes deviating from the pair's median by more
+// than MaxDeviation (a fraction, e.g. 0.02 for 2%) are discarded before the
+// remaining quotes are combined with Method.
+type ConsensusProvider struct {
+	Providers []RateProvider
+	Method    string
+	// TrimFraction is the share of quotes dropped from each end for the
+	// trimmed mean.
+	TrimFraction decimal.Decimal
+	MaxDeviation decimal.Decimal
+	// MinProviders is the number of providers that must answer for the sync
+	// to be accepted.
+	MinProviders int
+}
+
+type providerResult struct {
+	name  string
+	rates map[string]models.Rate
+	err   error
+}
+
+func (c *ConsensusProvider) Name() string {
+	return ConsensusProviderName
+}
+
+func (c *ConsensusProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	if len(c.Providers) == 0 {
+		return nil, errors.New("no rate providers configured")
+	}
+
+	results := make([]providerResult, len(c.Providers))
+	var wg sync.WaitGroup
+	for i, p := range c.Providers {
+		wg.Add(1)
+		go func(i int, p RateProvider) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					results[i] = providerResult{name: p.Name(), err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+			rates, err := p.GetRates(ctx)
+			results[i] = providerResult{name: p.Name(), rates: rates, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+
+	var errs []error
+	var succeeded []providerResult
+	for _, result := range results {
+		if result.err != nil {
+			log.Printf("Consensus: Provider %s failed, error: %v", result.name, result.err)
+			errs = append(errs, fmt.Errorf("%s: %w", result.name, result.err))
+			continue
+		}
+		log.Printf("Consensus: Provider %s returned %d rates", result.name, len(result.rates))
+		succeeded = append(succeeded, result)
+	}
+
+	minProviders := c.MinProviders
+	if minProviders < 1 {
+		minProviders = 1
+	}
+	if len(succeeded) < minProviders {
+		return nil, fmt.Errorf("only %d of %d providers answered, need %d: %w", len(succeeded), len(c.Providers), minProviders, errors.Join(errs...))
+	}
+
+	quotes := make(map[string][]models.Rate)
+	for _, result := range succeeded {
+		for key, rate := range result.rates {
+			if rate.Provider == "" {
+				rate.Provider = result.name
+			}
+			quotes[key] = append(quotes[key], rate)
+		}
+	}
+
+	rates := make(map[string]models.Rate, len(quotes))
+	discarded := 0
+	for key, pairQuotes := range quotes {
+		rate, dropped := c.aggregate(pairQuotes)
+		discarded += dropped
+		if dropped == len(pairQuotes) {
+			log.Printf("Consensus: Skipping %s, all %d quotes deviate more than %s from their median", key, dropped, c.MaxDeviation)
+			continue
+		}
+		rates[key] = rate
+	}
+
+	log.Printf("Consensus: Aggregated %d pairs from %d providers, discarded %d outlier quotes", len(rates), len(succeeded), discarded)
+	return rates, nil
+}
+
+// aggregate combines the quotes for one pair and reports how many were
+// discarded as outliers. When every quote is discarded the rate is
+// meaningless and the pair must be skipped.
+func (c *ConsensusProvider) aggregate(quotes []models.Rate) (models.Rate, int) {
+	median := medianOf(quotes)
+
+	kept := quotes
+	if c.MaxDeviation.IsPositive() && !median.IsZero() {
+		kept = make([]models.Rate, 0, len(quotes))
+		for _, quote := range quotes {
+			deviation := quote.Rate.Sub(median).Abs().Div(median.Abs())
+			if deviation.GreaterThan(c.MaxDeviation) {
+				log.Printf("Consensus: Discarding %s_%s = %s from %s, %s from median %s",
+					quote.Base, quote.Target, quote.Rate, quote.Provider, deviation.StringFixed(4), median)
+				continue
+			}
+			kept = append(kept, quote)
+		}
+	}
+
+	value := medianOf(kept)
+	if c.Method == ConsensusTrimmedMean {
+		value = c.trimmedMeanOf(kept)
+	}
+
+	names := make([]string, 0, len(kept))
+	var updatedAt int64
+	for _, quote := range kept {
+		names = append(names, quote.Provider)
+		if quote.UpdatedAt > updatedAt {
+			updatedAt = quote.UpdatedAt
+		}
+	}
+	sort.Strings(names)
+
+	return models.Rate{
+		Base:      quotes[0].Base,
+		Target:    quotes[0].Target,
+		Rate:      value,
+		Provider:  ConsensusProviderName + ":" + strings.Join(names, ","),
+		UpdatedAt: updatedAt,
+	}, len(quotes) - len(kept)
+}
+
+func sortedValues(quotes []models.Rate) []decimal.Decimal {
+	values := make([]decimal.Decimal, len(quotes))
+	for i, quote := range quotes {
+		values[i] = quote.Rate
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].LessThan(values[j]) })
+	return values
+}
+
+func medianOf(quotes []models.Rate) decimal.Decimal {
+	values := sortedValues(quotes)
+	n := len(values)
+	if n == 0 {
+		return decimal.Zero
+	}
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return values[n/2-1].Add(values[n/2]).Div(decimal.NewFromInt(2))
+}
+
+func (c *ConsensusProvider) trimmedMeanOf(quotes []models.Rate) decimal.Decimal {
+	values := sortedValues(quotes)
+	if len(values) == 0 {
+		return decimal.Zero
+	}
+	trim := int(c.TrimFraction.Mul(decimal.NewFromInt(int64(len(values)))).IntPart())
+	if 2*trim >= len(values) {
+		trim = (len(values) - 1) / 2
+	}
+	values = values[trim : len(values)-trim]
+	return decimal.Sum(values[0], values[1:]...).Div(decimal.NewFromInt(int64(len(values))))
+}
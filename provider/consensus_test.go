@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+type staticProvider struct {
+	name  string
+	rates map[string]models.Rate
+}
+
+func (p staticProvider) Name() string {
+	return p.name
+}
+
+func (p staticProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	return p.rates, nil
+}
+
+func quote(provider, base, target, rate string) map[string]models.Rate {
+	return map[string]models.Rate{
+		base + "_" + target: {Base: base, Target: target, Rate: decimal.RequireFromString(rate), Provider: provider},
+	}
+}
+
+func TestConsensusSkipsPairWhenEveryQuoteIsRejected(t *testing.T) {
+	for _, method := range []string{ConsensusMedian, ConsensusTrimmedMean} {
+		c := &ConsensusProvider{
+			Providers: []RateProvider{
+				staticProvider{name: "a", rates: quote("a", "USD", "EUR", "1.0")},
+				staticProvider{name: "b", rates: quote("b", "USD", "EUR", "1.1")},
+			},
+			Method:       method,
+			MaxDeviation: decimal.RequireFromString("0.02"),
+		}
+
+		rates, err := c.GetRates(context.Background())
+		if err != nil {
+			t.Fatalf("%s: GetRates returned error: %v", method, err)
+		}
+		if rate, ok := rates["USD_EUR"]; ok {
+			t.Errorf("%s: expected USD_EUR to be skipped, got %s", method, rate.Rate)
+		}
+	}
+}
+
+func TestConsensusDiscardsOutliers(t *testing.T) {
+	c := &ConsensusProvider{
+		Providers: []RateProvider{
+			staticProvider{name: "a", rates: quote("a", "USD", "EUR", "0.92")},
+			staticProvider{name: "b", rates: quote("b", "USD", "EUR", "0.921")},
+			staticProvider{name: "c", rates: quote("c", "USD", "EUR", "1.5")},
+		},
+		Method:       ConsensusMedian,
+		MaxDeviation: decimal.RequireFromString("0.02"),
+	}
+
+	rates, err := c.GetRates(context.Background())
+	if err != nil {
+		t.Fatalf("GetRates returned error: %v", err)
+	}
+	rate := rates["USD_EUR"]
+	if want := decimal.RequireFromString("0.9205"); !rate.Rate.Equal(want) {
+		t.Errorf("rate = %s, want %s", rate.Rate, want)
+	}
+	if want := "consensus:a,b"; rate.Provider != want {
+		t.Errorf("provider = %q, want %q", rate.Provider, want)
+	}
+}
+
+func TestAggregatesOfNoQuotesAreZero(t *testing.T) {
+	c := &ConsensusProvider{TrimFraction: decimal.RequireFromString("0.2")}
+	if value := medianOf(nil); !value.IsZero() {
+		t.Errorf("medianOf(nil) = %s, want 0", value)
+	}
+	if value := c.trimmedMeanOf(nil); !value.IsZero() {
+		t.Errorf("trimmedMeanOf(nil) = %s, want 0", value)
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"encoding/xml"
+	"errors"
+)
+
+const (
+	ECBProviderName = "ecb"
+
+	ECBDailyURL       = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ECBHistory90DURL  = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ECBHistoryFullURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+// ECBProvider reads the European Central Bank euro foreign exchange reference
+// rates. URL is the daily feed; HistoryURL, when set, is one of the 90-day or
+// full historical files used to backfill the rate history.
+type ECBProvider struct {
+	URL        string
+	HistoryURL string
+	Adapter    *ECBAdapter
+	Client     *HTTPClient
+}
+
+// ecbEnvelope mirrors the gesmes:Envelope document. Every feed has the same
+// shape; the daily file just holds a single day.
+type ecbEnvelope struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Days    []ecbDay `xml:"Cube>Cube"`
+}
+
+type ecbDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ecbRate `xml:"Cube"`
+}
+
+type ecbRate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+func (e *ECBProvider) Name() string {
+	return ECBProviderName
+}
+
+func (e *ECBProvider) CircuitState() CircuitState {
+	return e.Client.Breaker.State()
+}
+
+func (e *ECBProvider) fetchEnvelope(ctx context.Context, url string) (*ecbEnvelope, error) {
+	body, err := e.Client.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ecbEnvelope
+	err = xml.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (e *ECBProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	data, err := e.fetchEnvelope(ctx, e.URL)
+	if err != nil {
+		return nil, err
+	}
+	return e.Adapter.Adapt(data)
+}
+
+func (e *ECBProvider) GetHistoricalRates(ctx context.Context) ([]models.Rate, error) {
+	if e.HistoryURL == "" {
+		return nil, errors.New("no ECB history feed configured")
+	}
+	data, err := e.fetchEnvelope(ctx, e.HistoryURL)
+	if err != nil {
+		return nil, err
+	}
+	return e.Adapter.AdaptHistory(data)
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"assignment1/models"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"sort"
+	"time"
+	_ "time/tzdata"
+)
+
+const (
+	ecbQuoteCurrency = "EUR"
+	// ecbRebasePrecision is the number of decimal places kept when rebasing.
+	ecbRebasePrecision = 20
+	// ecbPublicationHour is when the ECB publishes the day's reference rates,
+	// 16:00 Frankfurt time.
+	ecbPublicationHour = 16
+)
+
+// ecbLocation is Frankfurt time. time/tzdata is embedded so it loads on hosts
+// without a time zone database.
+var ecbLocation, _ = time.LoadLocation("Europe/Berlin")
+
+// ECBAdapter turns the ECB's EUR-based quotes into BaseCurrency-based rates,
+// e.g. with a USD base USD_GBP = EUR_GBP / EUR_USD. Rates are stamped with
+// their publication time (16:00 Frankfurt time on the reference date) rather
+// than the fetch time, so repeated syncs of the same publication map to the
+// same history rows and as_of lookups never see a rate before it was out.
+type ECBAdapter struct {
+	BaseCurrency string
+}
+
+func (a *ECBAdapter) Adapt(rawData interface{}) (map[string]models.Rate, error) {
+	data, ok := rawData.(*ecbEnvelope)
+	if !ok {
+		return nil, errors.New("invalid data type for ECBAdapter")
+	}
+	if len(data.Days) == 0 {
+		return nil, errors.New("ECB feed contains no reference rates")
+	}
+
+	latest := data.Days[0]
+	for _, day := range data.Days[1:] {
+		if day.Time > latest.Time {
+			latest = day
+		}
+	}
+	return a.adaptDay(latest)
+}
+
+// AdaptHistory rebases every day of a historical feed, oldest first.
+func (a *ECBAdapter) AdaptHistory(rawData interface{}) ([]models.Rate, error) {
+	data, ok := rawData.(*ecbEnvelope)
+	if !ok {
+		return nil, errors.New("invalid data type for ECBAdapter")
+	}
+
+	days := append([]ecbDay(nil), data.Days...)
+	sort.Slice(days, func(i, j int) bool { return days[i].Time < days[j].Time })
+
+	var history []models.Rate
+	for _, day := range days {
+		rates, err := a.adaptDay(day)
+		if err != nil {
+			return nil, err
+		}
+		for _, rate := range rates {
+			history = append(history, rate)
+		}
+	}
+	return history, nil
+}
+
+func (a *ECBAdapter) adaptDay(day ecbDay) (map[string]models.Rate, error) {
+	date, err := time.ParseInLocation(time.DateOnly, day.Time, ecbLocation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECB reference date %q: %w", day.Time, err)
+	}
+
+	eurQuotes := map[string]decimal.Decimal{ecbQuoteCurrency: decimal.NewFromInt(1)}
+	for _, quote := range day.Rates {
+		value, err := decimal.NewFromString(quote.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECB rate %q for %s on %s: %w", quote.Rate, quote.Currency, day.Time, err)
+		}
+		eurQuotes[quote.Currency] = value
+	}
+
+	eurToBase, ok := eurQuotes[a.BaseCurrency]
+	if !ok || eurToBase.IsZero() {
+		return nil, fmt.Errorf("ECB feed for %s has no rate for base currency %s", day.Time, a.BaseCurrency)
+	}
+
+	updateTime := time.Date(date.Year(), date.Month(), date.Day(), ecbPublicationHour, 0, 0, 0, ecbLocation).Unix()
+	rates := make(map[string]models.Rate, len(eurQuotes))
+	for code, eurToTarget := range eurQuotes {
+		key := a.BaseCurrency + "_" + code
+		rates[key] = models.Rate{
+			Rate:      eurToTarget.DivRound(eurToBase, ecbRebasePrecision),
+			Base:      a.BaseCurrency,
+			Target:    code,
+			Provider:  ECBProviderName,
+			UpdatedAt: updateTime,
+		}
+	}
+	return rates, nil
+}
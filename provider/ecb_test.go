@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"encoding/xml"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadECBFixture(t *testing.T, name string) *ecbEnvelope {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	var data ecbEnvelope
+	if err := xml.Unmarshal(body, &data); err != nil {
+		t.Fatalf("decoding fixture: %v", err)
+	}
+	return &data
+}
+
+func TestECBAdaptRebasesToGlobalBase(t *testing.T) {
+	adapter := &ECBAdapter{BaseCurrency: "USD"}
+	rates, err := adapter.Adapt(loadECBFixture(t, "eurofxref-daily.xml"))
+	if err != nil {
+		t.Fatalf("Adapt returned error: %v", err)
+	}
+
+	if len(rates) != 5 {
+		t.Errorf("got %d rates, want 5 (four quotes plus EUR)", len(rates))
+	}
+	eurUSD := decimal.RequireFromString("1.1512")
+	want := map[string]decimal.Decimal{
+		"USD_USD": decimal.NewFromInt(1),
+		"USD_EUR": decimal.NewFromInt(1).DivRound(eurUSD, ecbRebasePrecision),
+		"USD_GBP": decimal.RequireFromString("0.85030").DivRound(eurUSD, ecbRebasePrecision),
+		"USD_JPY": decimal.RequireFromString("165.84").DivRound(eurUSD, ecbRebasePrecision),
+	}
+	// 16:00 CEST on the reference date.
+	published := time.Date(2025, 6, 13, 14, 0, 0, 0, time.UTC).Unix()
+	for key, rate := range want {
+		got, ok := rates[key]
+		if !ok {
+			t.Errorf("missing %s", key)
+			continue
+		}
+		if !got.Rate.Equal(rate) {
+			t.Errorf("%s = %s, want %s", key, got.Rate, rate)
+		}
+		if got.Base != "USD" || got.Provider != ECBProviderName {
+			t.Errorf("%s has base %s and provider %s", key, got.Base, got.Provider)
+		}
+		if got.UpdatedAt != published {
+			t.Errorf("%s stamped %s, want publication time %s", key, time.Unix(got.UpdatedAt, 0).UTC(), time.Unix(published, 0).UTC())
+		}
+	}
+}
+
+func TestECBAdaptKeepsEURQuotesForEURBase(t *testing.T) {
+	adapter := &ECBAdapter{BaseCurrency: "EUR"}
+	rates, err := adapter.Adapt(loadECBFixture(t, "eurofxref-daily.xml"))
+	if err != nil {
+		t.Fatalf("Adapt returned error: %v", err)
+	}
+	if got := rates["EUR_GBP"].Rate; !got.Equal(decimal.RequireFromString("0.8503")) {
+		t.Errorf("EUR_GBP = %s, want 0.8503", got)
+	}
+}
+
+func TestECBAdaptHistoryRebasesEveryDayOldestFirst(t *testing.T) {
+	adapter := &ECBAdapter{BaseCurrency: "USD"}
+	history, err := adapter.AdaptHistory(loadECBFixture(t, "eurofxref-hist-90d.xml"))
+	if err != nil {
+		t.Fatalf("AdaptHistory returned error: %v", err)
+	}
+
+	if len(history) != 9 {
+		t.Fatalf("got %d rates, want 9 (three days of USD, GBP and EUR)", len(history))
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].UpdatedAt < history[i-1].UpdatedAt {
+			t.Fatalf("history not ordered oldest first at %d", i)
+		}
+	}
+	// 16:00 CET, as January is outside daylight saving time.
+	if want := time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC).Unix(); history[0].UpdatedAt != want {
+		t.Errorf("first rate stamped %s, want %s", time.Unix(history[0].UpdatedAt, 0).UTC(), time.Unix(want, 0).UTC())
+	}
+	for _, rate := range history {
+		if rate.Base != "USD" {
+			t.Errorf("rate %s_%s not rebased to USD", rate.Base, rate.Target)
+		}
+		if rate.Target == "GBP" && rate.UpdatedAt == history[0].UpdatedAt {
+			want := decimal.RequireFromString("0.84535").DivRound(decimal.RequireFromString("1.0390"), ecbRebasePrecision)
+			if !rate.Rate.Equal(want) {
+				t.Errorf("USD_GBP on 2025-01-20 = %s, want %s", rate.Rate, want)
+			}
+		}
+	}
+}
+
+func TestECBAdaptRejectsMissingBaseCurrency(t *testing.T) {
+	adapter := &ECBAdapter{BaseCurrency: "ARS"}
+	if _, err := adapter.Adapt(loadECBFixture(t, "eurofxref-daily.xml")); err == nil || !strings.Contains(err.Error(), "ARS") {
+		t.Errorf("Adapt error = %v, want missing base currency ARS", err)
+	}
+	if _, err := adapter.AdaptHistory(loadECBFixture(t, "eurofxref-hist-90d.xml")); err == nil || !strings.Contains(err.Error(), "ARS") {
+		t.Errorf("AdaptHistory error = %v, want missing base currency ARS", err)
+	}
+}
+
+func TestECBAdaptRejectsMalformedRate(t *testing.T) {
+	adapter := &ECBAdapter{BaseCurrency: "USD"}
+	_, err := adapter.Adapt(loadECBFixture(t, "eurofxref-malformed-rate.xml"))
+	if err == nil || !strings.Contains(err.Error(), `"0,85030"`) {
+		t.Errorf("Adapt error = %v, want invalid rate 0,85030", err)
+	}
+}
+
+func TestECBProviderFetchesDailyFeed(t *testing.T) {
+	feed, err := os.ReadFile(filepath.Join("testdata", "eurofxref-daily.xml"))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write(feed)
+	}))
+	defer server.Close()
+
+	ecb := &ECBProvider{
+		URL:     server.URL,
+		Adapter: &ECBAdapter{BaseCurrency: "USD"},
+		Client:  NewHTTPClient(ECBProviderName, HTTPClientConfig{MaxRetries: new(int)}),
+	}
+	rates, err := ecb.GetRates(context.Background())
+	if err != nil {
+		t.Fatalf("GetRates returned error: %v", err)
+	}
+	if _, ok := rates["USD_CHF"]; !ok {
+		t.Errorf("USD_CHF missing from %d rates", len(rates))
+	}
+	if _, err := ecb.GetHistoricalRates(context.Background()); err == nil {
+		t.Error("GetHistoricalRates without a history feed should fail")
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+const FailoverProviderName = "failover"
+
+// FailoverProvider asks its Providers in order and returns the first result
+// that is both error-free and complete. Completeness is checked per provider,
+// so a provider quoting a few dozen currencies is not held to the standard of
+// one quoting hundreds; providers without an entry are used whenever they
+// answer.
+type FailoverProvider struct {
+	Providers    []RateProvider
+	BaseCurrency string
+	// Completeness maps provider names to their check.
+	Completeness map[string]Completeness
+}
+
+// Completeness rejects a result holding fewer than MinRates rates or lacking
+// a base currency rate for any of the RequiredCurrencies. The zero value
+// accepts any result.
+type Completeness struct {
+	MinRates           int
+	RequiredCurrencies []string
+}
+
+func (f *FailoverProvider) Name() string {
+	return FailoverProviderName
+}
+
+func (f *FailoverProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	if len(f.Providers) == 0 {
+		return nil, errors.New("no rate providers configured")
+	}
+
+	var errs []error
+	for i, p := range f.Providers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		log.Printf("Failover: Fetching rates from provider %s (%d/%d)", p.Name(), i+1, len(f.Providers))
+		rates, err := p.GetRates(ctx)
+		if err != nil {
+			log.Printf("Failover: Provider %s failed, error: %v", p.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+		if err := f.checkComplete(f.Completeness[p.Name()], rates); err != nil {
+			log.Printf("Failover: Provider %s returned incomplete data, error: %v", p.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+			continue
+		}
+
+		for key, rate := range rates {
+			if rate.Provider == "" {
+				rate.Provider = p.Name()
+				rates[key] = rate
+			}
+		}
+		log.Printf("Failover: Using %d rates from provider %s", len(rates), p.Name())
+		return rates, nil
+	}
+
+	return nil, fmt.Errorf("all rate providers failed: %w", errors.Join(errs...))
+}
+
+func (f *FailoverProvider) checkComplete(check Completeness, rates map[string]models.Rate) error {
+	if len(rates) < check.MinRates {
+		return fmt.Errorf("got %d rates, expected at least %d", len(rates), check.MinRates)
+	}
+	for _, code := range check.RequiredCurrencies {
+		if _, ok := rates[f.BaseCurrency+"_"+code]; !ok {
+			return fmt.Errorf("missing required rate %s_%s", f.BaseCurrency, code)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"testing"
+)
+
+func rateSet(provider string, targets ...string) map[string]models.Rate {
+	rates := make(map[string]models.Rate)
+	for _, target := range targets {
+		for key, rate := range quote(provider, "USD", target, "1.5") {
+			rates[key] = rate
+		}
+	}
+	return rates
+}
+
+func TestFailoverChecksCompletenessPerProvider(t *testing.T) {
+	tests := []struct {
+		name         string
+		completeness map[string]Completeness
+		want         string
+	}{
+		{"no checks", nil, "big"},
+		{"first below its minimum", map[string]Completeness{"big": {MinRates: 4}}, "small"},
+		{"first missing a required currency", map[string]Completeness{"big": {RequiredCurrencies: []string{"JPY"}}}, "small"},
+		{"check on the fallback only", map[string]Completeness{"small": {MinRates: 100}}, "big"},
+	}
+	for _, tt := range tests {
+		f := &FailoverProvider{
+			Providers: []RateProvider{
+				staticProvider{name: "big", rates: rateSet("", "EUR", "GBP", "ARS")},
+				staticProvider{name: "small", rates: rateSet("", "EUR")},
+			},
+			BaseCurrency: "USD",
+			Completeness: tt.completeness,
+		}
+		rates, err := f.GetRates(context.Background())
+		if err != nil {
+			t.Errorf("%s: GetRates returned error: %v", tt.name, err)
+			continue
+		}
+		if got := rates["USD_EUR"].Provider; got != tt.want {
+			t.Errorf("%s: rates from %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFailoverFailsWhenNoProviderIsComplete(t *testing.T) {
+	f := &FailoverProvider{
+		Providers:    []RateProvider{staticProvider{name: "small", rates: rateSet("", "EUR")}},
+		BaseCurrency: "USD",
+		Completeness: map[string]Completeness{"small": {MinRates: 2}},
+	}
+	if _, err := f.GetRates(context.Background()); err == nil {
+		t.Error("GetRates accepted a result below the provider's minimum")
+	}
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FileProviderName = "file"
+
+	defaultFilePollInterval = 5 * time.Second
+)
+
+// FileProvider serves rates from a local CSV, JSON or YAML file, chosen by
+// extension, so the service can run without network access. The file is
+// polled for changes; a file that fails to parse keeps the previous rates.
+//
+// JSON and YAML files share one layout, where pairs is optional and holds
+// quotes that are not based on base:
+//
+//	base: USD
+//	timestamp: 1718000000
+//	rates:
+//	  EUR: 0.92
+//	pairs:
+//	  - {base: EUR, target: GBP, rate: 0.85}
+//
+// CSV files have a base,target,rate[,updated_at] header.
+type FileProvider struct {
+	Path         string
+	PollInterval time.Duration
+	// OnChange is called after the file has been reloaded successfully. Set
+	// it before calling Watch.
+	OnChange func()
+
+	mutex   sync.RWMutex
+	rates   map[string]models.Rate
+	modTime time.Time
+	err     error
+}
+
+type fileDocument struct {
+	Base      string            `yaml:"base"`
+	Timestamp int64             `yaml:"timestamp"`
+	Rates     map[string]string `yaml:"rates"`
+	Pairs     []struct {
+		Base   string `yaml:"base"`
+		Target string `yaml:"target"`
+		Rate   string `yaml:"rate"`
+	} `yaml:"pairs"`
+}
+
+// NewFileProvider loads path once. Call Watch to pick up later changes.
+func NewFileProvider(path string, pollInterval time.Duration) *FileProvider {
+	if pollInterval <= 0 {
+		pollInterval = defaultFilePollInterval
+	}
+	f := &FileProvider{Path: path, PollInterval: pollInterval}
+	f.reload()
+	return f
+}
+
+// Watch starts polling the file for changes in the background.
+func (f *FileProvider) Watch() {
+	go f.watch()
+}
+
+func (f *FileProvider) Name() string {
+	return FileProviderName
+}
+
+func (f *FileProvider) GetRates(_ context.Context) (map[string]models.Rate, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	if f.rates == nil {
+		if f.err != nil {
+			return nil, f.err
+		}
+		return nil, fmt.Errorf("rates file %s has not been loaded", f.Path)
+	}
+
+	rates := make(map[string]models.Rate, len(f.rates))
+	for key, rate := range f.rates {
+		rates[key] = rate
+	}
+	return rates, nil
+}
+
+func (f *FileProvider) watch() {
+	ticker := time.NewTicker(f.PollInterval)
+	for range ticker.C {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			log.Printf("File Provider Error: Cannot stat %s, error: %v", f.Path, err)
+			continue
+		}
+
+		f.mutex.RLock()
+		changed := !info.ModTime().Equal(f.modTime)
+		f.mutex.RUnlock()
+
+		if changed {
+			log.Printf("File Provider: %s changed, reloading", f.Path)
+			if f.reload() && f.OnChange != nil {
+				f.OnChange()
+			}
+		}
+	}
+}
+
+// reload parses the file and swaps in its rates, reporting whether it did.
+func (f *FileProvider) reload() bool {
+	info, err := os.Stat(f.Path)
+	if err == nil {
+		var rates map[string]models.Rate
+		rates, err = f.load(info.ModTime())
+		if err == nil {
+			f.mutex.Lock()
+			f.rates = rates
+			f.modTime = info.ModTime()
+			f.err = nil
+			f.mutex.Unlock()
+			log.Printf("File Provider: Loaded %d rates from %s", len(rates), f.Path)
+			return true
+		}
+	}
+
+	log.Printf("File Provider Error: Failed to load %s, keeping previous rates, error: %v", f.Path, err)
+	f.mutex.Lock()
+	if info != nil {
+		f.modTime = info.ModTime()
+	}
+	f.err = err
+	f.mutex.Unlock()
+	return false
+}
+
+func (f *FileProvider) load(modTime time.Time) (map[string]models.Rate, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(f.Path)); ext {
+	case ".csv":
+		return parseRatesCSV(file, modTime.Unix())
+	case ".json", ".yaml", ".yml":
+		// JSON is a subset of YAML, so one decoder handles both.
+		var doc fileDocument
+		if err := yaml.NewDecoder(file).Decode(&doc); err != nil {
+			return nil, err
+		}
+		return doc.toRates(modTime.Unix())
+	default:
+		return nil, fmt.Errorf("unsupported rates file extension %q, expected .csv, .json, .yaml or .yml", ext)
+	}
+}
+
+func (d fileDocument) toRates(defaultTime int64) (map[string]models.Rate, error) {
+	updatedAt := d.Timestamp
+	if updatedAt == 0 {
+		updatedAt = defaultTime
+	}
+
+	rates := make(map[string]models.Rate, len(d.Rates)+len(d.Pairs))
+	add := func(base, target, value string) error {
+		if base == "" || target == "" {
+			return errors.New("rates file entry is missing base or target")
+		}
+		rate, err := decimal.NewFromString(value)
+		if err != nil {
+			return fmt.Errorf("invalid rate %q for %s_%s: %w", value, base, target, err)
+		}
+		rates[base+"_"+target] = models.Rate{
+			Base:      base,
+			Target:    target,
+			Rate:      rate,
+			Provider:  FileProviderName,
+			UpdatedAt: updatedAt,
+		}
+		return nil
+	}
+
+	for target, value := range d.Rates {
+		if err := add(d.Base, target, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, pair := range d.Pairs {
+		if err := add(pair.Base, pair.Target, pair.Rate); err != nil {
+			return nil, err
+		}
+	}
+	return rates, nil
+}
+
+func parseRatesCSV(r io.Reader, defaultTime int64) (map[string]models.Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"base", "target", "rate"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing column %q", required)
+		}
+	}
+	updatedAtColumn, hasUpdatedAt := columns["updated_at"]
+
+	rates := make(map[string]models.Rate)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		base, target := record[columns["base"]], record[columns["target"]]
+		if base == "" || target == "" {
+			return nil, errors.New("rates file entry is missing base or target")
+		}
+		rate, err := decimal.NewFromString(record[columns["rate"]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q for %s_%s: %w", record[columns["rate"]], base, target, err)
+		}
+
+		updatedAt := defaultTime
+		if hasUpdatedAt && record[updatedAtColumn] != "" {
+			updatedAt, err = strconv.ParseInt(record[updatedAtColumn], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid updated_at %q for %s_%s: %w", record[updatedAtColumn], base, target, err)
+			}
+		}
+
+		rates[base+"_"+target] = models.Rate{
+			Base:      base,
+			Target:    target,
+			Rate:      rate,
+			Provider:  FileProviderName,
+			UpdatedAt: updatedAt,
+		}
+	}
+	return rates, nil
+}
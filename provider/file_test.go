@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"github.com/shopspring/decimal"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRatesCSVRejectsMissingBaseOrTarget(t *testing.T) {
+	for _, body := range []string{
+		"base,target,rate\n,EUR,0.92\n",
+		"base,target,rate\nUSD,,0.92\n",
+	} {
+		if _, err := parseRatesCSV(strings.NewReader(body), 0); err == nil {
+			t.Errorf("parseRatesCSV(%q) succeeded, want missing base or target", body)
+		}
+	}
+}
+
+func TestParseRatesCSV(t *testing.T) {
+	body := "base,target,rate,updated_at\nUSD,EUR,0.92,1718000000\nEUR,GBP,0.85,\n"
+	rates, err := parseRatesCSV(strings.NewReader(body), 42)
+	if err != nil {
+		t.Fatalf("parseRatesCSV returned error: %v", err)
+	}
+	if rate := rates["USD_EUR"]; rate.Rate.String() != "0.92" || rate.UpdatedAt != 1718000000 {
+		t.Errorf("USD_EUR = %s at %d", rate.Rate, rate.UpdatedAt)
+	}
+	if rate := rates["EUR_GBP"]; rate.UpdatedAt != 42 || rate.Provider != FileProviderName {
+		t.Errorf("EUR_GBP updated at %d by %q, want 42 by %q", rate.UpdatedAt, rate.Provider, FileProviderName)
+	}
+}
+
+func TestFileProviderWatchCallsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	if err := os.WriteFile(path, []byte("base,target,rate\nUSD,EUR,0.92\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFileProvider(path, 10*time.Millisecond)
+	changed := make(chan struct{}, 1)
+	f.OnChange = func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	f.Watch()
+
+	later := time.Now().Add(time.Second)
+	if err := os.WriteFile(path, []byte("base,target,rate\nUSD,EUR,0.93\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Make the change visible on file systems with coarse timestamps.
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnChange not called after the file changed")
+	}
+	rates, err := f.GetRates(context.Background())
+	if err != nil {
+		t.Fatalf("GetRates returned error: %v", err)
+	}
+	if got := rates["USD_EUR"].Rate.String(); got != "0.93" {
+		t.Errorf("USD_EUR = %s after reload, want 0.93", got)
+	}
+}
+
+func TestFileProviderLoadsJSONAndYAML(t *testing.T) {
+	want := map[string]string{
+		"USD_EUR": "0.92",
+		"USD_JPY": "157.123456789012345678",
+		"USD_BTC": "0.000015",
+		"USD_ARS": "905.25",
+		"EUR_GBP": "0.85",
+	}
+	for _, name := range []string{"rates.json", "rates.yaml"} {
+		rates, err := NewFileProvider(filepath.Join("testdata", name), time.Minute).GetRates(context.Background())
+		if err != nil {
+			t.Fatalf("%s: GetRates returned error: %v", name, err)
+		}
+		if len(rates) != len(want) {
+			t.Errorf("%s: got %d rates, want %d", name, len(rates), len(want))
+		}
+		for key, value := range want {
+			rate, ok := rates[key]
+			if !ok {
+				t.Errorf("%s: missing %s", name, key)
+				continue
+			}
+			if !rate.Rate.Equal(decimal.RequireFromString(value)) {
+				t.Errorf("%s: %s = %s, want %s", name, key, rate.Rate, value)
+			}
+			if rate.UpdatedAt != 1718000000 || rate.Provider != FileProviderName {
+				t.Errorf("%s: %s updated at %d by %q, want the file's timestamp", name, key, rate.UpdatedAt, rate.Provider)
+			}
+		}
+	}
+}
+
+func TestFileProviderStampsRatesWithModTimeWithoutTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.yaml")
+	if err := os.WriteFile(path, []byte("base: USD\nrates:\n  EUR: 0.92\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := NewFileProvider(path, time.Minute).GetRates(context.Background())
+	if err != nil {
+		t.Fatalf("GetRates returned error: %v", err)
+	}
+	if got := rates["USD_EUR"].UpdatedAt; got != modTime.Unix() {
+		t.Errorf("USD_EUR updated at %d, want the modification time %d", got, modTime.Unix())
+	}
+}
+
+func TestFileProviderRejectsMalformedFiles(t *testing.T) {
+	for _, name := range []string{"rates-malformed.json", "rates-invalid-rate.yaml", "rates-missing-target.yaml"} {
+		if rates, err := NewFileProvider(filepath.Join("testdata", name), time.Minute).GetRates(context.Background()); err == nil {
+			t.Errorf("%s: GetRates returned %d rates, want an error", name, len(rates))
+		}
+	}
+}
+
+func TestFileProviderWatchReloadsJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rates.json")
+	copyFixture(t, "rates.json", path, time.Now().Add(-time.Hour))
+
+	f := NewFileProvider(path, 10*time.Millisecond)
+	changed := make(chan struct{}, 1)
+	f.OnChange = func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	f.Watch()
+
+	if err := os.WriteFile(path, []byte(`{"base": "USD", "timestamp": 1718000600, "rates": {"EUR": 0.93}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnChange not called after the file changed")
+	}
+	rates, err := f.GetRates(context.Background())
+	if err != nil {
+		t.Fatalf("GetRates returned error: %v", err)
+	}
+	if rate := rates["USD_EUR"]; rate.Rate.String() != "0.93" || rate.UpdatedAt != 1718000600 || len(rates) != 1 {
+		t.Errorf("after reload got %d rates with USD_EUR = %s at %d, want only 0.93 at 1718000600", len(rates), rate.Rate, rate.UpdatedAt)
+	}
+
+	// A broken edit keeps the last good rates and does not trigger a sync.
+	copyFixture(t, "rates-malformed.json", path, time.Now().Add(time.Hour))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		f.mutex.RLock()
+		failed := f.err != nil
+		f.mutex.RUnlock()
+		if failed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("malformed file was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-changed:
+		t.Error("OnChange called for a malformed file")
+	default:
+	}
+	if rates, err := f.GetRates(context.Background()); err != nil || rates["USD_EUR"].Rate.String() != "0.93" {
+		t.Errorf("after a malformed edit GetRates = %v, %v, want the previous rates", rates["USD_EUR"].Rate, err)
+	}
+}
+
+// copyFixture writes the named testdata file to path with the given
+// modification time.
+func copyFixture(t *testing.T, name, path string, modTime time.Time) {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
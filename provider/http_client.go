@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultHTTPTimeout          = 10 * time.Second
+	DefaultHTTPMaxRetries       = 3
+	DefaultHTTPBaseBackoff      = 500 * time.Millisecond
+	DefaultHTTPMaxBackoff       = 30 * time.Second
+	DefaultCircuitFailures      = 5
+	DefaultCircuitOpenTimeout   = time.Minute
+	maxProviderResponseBytes    = 32 << 20
+	maxProviderErrorBodySnippet = 200
+)
+
+// HTTPClientConfig tunes an HTTPClient. Zero values use the defaults above.
+type HTTPClientConfig struct {
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt; nil uses
+	// DefaultHTTPMaxRetries and 0 disables retries.
+	MaxRetries  *int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// FailureThreshold consecutive failed fetches open the circuit for
+	// OpenTimeout.
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// HTTPClient fetches provider feeds with a per-request timeout, retries with
+// exponential backoff and jitter, and a circuit breaker around the upstream.
+type HTTPClient struct {
+	Config  HTTPClientConfig
+	Breaker *CircuitBreaker
+	client  *http.Client
+}
+
+// StatusError is returned for non-2xx responses.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s returned %d %s: %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// retryable reports whether the upstream may succeed if asked again.
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+func NewHTTPClient(name string, cfg HTTPClientConfig) *HTTPClient {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultHTTPTimeout
+	}
+	retries := DefaultHTTPMaxRetries
+	if cfg.MaxRetries != nil {
+		retries = max(*cfg.MaxRetries, 0)
+	}
+	cfg.MaxRetries = &retries
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = DefaultHTTPBaseBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultHTTPMaxBackoff
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultCircuitFailures
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+	return &HTTPClient{
+		Config: cfg,
+		Breaker: &CircuitBreaker{
+			Name:             name,
+			FailureThreshold: cfg.FailureThreshold,
+			OpenTimeout:      cfg.OpenTimeout,
+		},
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Get fetches rawURL and returns the response body. A call rejected by an open
+// circuit fails fast with ErrCircuitOpen; otherwise the outcome after retries
+// counts as one success or failure towards the breaker. Cancelling ctx stops
+// the request and any pending retry without counting as a failure.
+func (c *HTTPClient) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	if err := c.Breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Breaker.Name, err)
+	}
+	body, err := c.getWithRetry(ctx, rawURL)
+	if err != nil && ctx.Err() != nil {
+		c.Breaker.Abandon()
+		return nil, err
+	}
+	if err != nil {
+		c.Breaker.Failure()
+		return nil, err
+	}
+	c.Breaker.Success()
+	return body, nil
+}
+
+func (c *HTTPClient) getWithRetry(ctx context.Context, rawURL string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, retryAfter, err := c.getOnce(ctx, rawURL)
+		if err == nil {
+			return body, nil
+		}
+		if statusErr, ok := err.(*StatusError); ok && !statusErr.retryable() {
+			return nil, err
+		}
+		if attempt >= *c.Config.MaxRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt)
+		if retryAfter > 0 {
+			wait = min(retryAfter, c.Config.MaxBackoff)
+		}
+		log.Printf("Provider HTTP: %s attempt %d failed, retrying in %s, error: %v", c.Breaker.Name, attempt+1, wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// getOnce performs a single request. For error responses it also returns the
+// delay requested by the Retry-After header, if any.
+func (c *HTTPClient) getOnce(ctx context.Context, rawURL string) ([]byte, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = withoutQuery(urlErr.URL)
+		}
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponseBytes))
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := string(body)
+		if len(snippet) > maxProviderErrorBodySnippet {
+			snippet = snippet[:maxProviderErrorBodySnippet]
+		}
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), &StatusError{
+			URL:        withoutQuery(rawURL),
+			StatusCode: resp.StatusCode,
+			Body:       snippet,
+		}
+	}
+	return body, 0, nil
+}
+
+// withoutQuery strips the query string, which carries API keys for some
+// providers, before a URL is logged.
+func withoutQuery(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	u.RawQuery = ""
+	return u.Redacted()
+}
+
+// backoff returns a random delay up to BaseBackoff * 2^attempt, capped at
+// MaxBackoff ("full jitter"), so clients retrying together spread out.
+func (c *HTTPClient) backoff(attempt int) time.Duration {
+	ceiling := c.Config.MaxBackoff
+	if attempt < 30 {
+		ceiling = min(c.Config.BaseBackoff<<attempt, c.Config.MaxBackoff)
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// parseRetryAfter accepts delay-seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
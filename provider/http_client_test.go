@@ -0,0 +1,202 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedServer answers with statuses in turn, repeating the last one, and
+// counts the requests it received.
+func scriptedServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte("ok"))
+		} else {
+			w.Write([]byte("upstream says no"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func retries(n int) *int {
+	return &n
+}
+
+func testClient(maxRetries *int) *HTTPClient {
+	return NewHTTPClient("test", HTTPClientConfig{
+		MaxRetries:       maxRetries,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	})
+}
+
+func TestHTTPClientRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries *int
+		statuses   []int
+		hits       int32
+		statusCode int
+	}{
+		{"server errors until success", retries(3), []int{503, 500, 200}, 3, 0},
+		{"rate limited until success", retries(3), []int{429, 200}, 2, 0},
+		{"retries exhausted", retries(2), []int{502}, 3, 502},
+		{"default retries", nil, []int{500}, DefaultHTTPMaxRetries + 1, 500},
+		{"retries disabled", retries(0), []int{503, 200}, 1, 503},
+		{"unauthorized", retries(3), []int{401, 200}, 1, 401},
+		{"not found", retries(3), []int{404, 200}, 1, 404},
+	}
+	for _, tt := range tests {
+		server, hits := scriptedServer(t, nil, tt.statuses...)
+		body, err := testClient(tt.maxRetries).Get(context.Background(), server.URL)
+		if got := hits.Load(); got != tt.hits {
+			t.Errorf("%s: %d requests, want %d", tt.name, got, tt.hits)
+		}
+		if tt.statusCode == 0 {
+			if err != nil || string(body) != "ok" {
+				t.Errorf("%s: Get = %q, %v, want ok", tt.name, body, err)
+			}
+			continue
+		}
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.statusCode {
+			t.Errorf("%s: Get error = %v, want a %d StatusError", tt.name, err, tt.statusCode)
+			continue
+		}
+		if statusErr.Body != "upstream says no" {
+			t.Errorf("%s: StatusError body = %q", tt.name, statusErr.Body)
+		}
+	}
+}
+
+func TestHTTPClientHonoursRetryAfter(t *testing.T) {
+	server, hits := scriptedServer(t, http.Header{"Retry-After": {"1"}}, 429, 200)
+	client := NewHTTPClient("test", HTTPClientConfig{
+		MaxRetries:  retries(1),
+		BaseBackoff: time.Millisecond,
+		// Retry-After is capped at MaxBackoff, which keeps the test quick.
+		MaxBackoff: 50 * time.Millisecond,
+	})
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), server.URL); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("retried after %s, want the Retry-After delay capped at 50ms", elapsed)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("%d requests, want 2", hits.Load())
+	}
+}
+
+func TestHTTPClientRedactsQueryStrings(t *testing.T) {
+	server, _ := scriptedServer(t, nil, 401)
+	_, err := testClient(retries(0)).Get(context.Background(), server.URL+"/latest.json?app_id=s3cret")
+	if err == nil || strings.Contains(err.Error(), "s3cret") || !strings.Contains(err.Error(), "/latest.json") {
+		t.Errorf("status error = %v, want the URL without its query", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = testClient(retries(0)).Get(context.Background(), closed.URL+"/latest.json?app_id=s3cret")
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("network error = %v, want the URL without its query", err)
+	}
+}
+
+func TestHTTPClientOpensCircuitAfterFailures(t *testing.T) {
+	server, hits := scriptedServer(t, nil, 500)
+	client := testClient(retries(0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(context.Background(), server.URL); err == nil {
+			t.Fatal("Get succeeded against a failing upstream")
+		}
+	}
+	if state := client.Breaker.State(); state != CircuitOpen {
+		t.Fatalf("circuit %s after 2 failures, want open", state)
+	}
+	if _, err := client.Get(context.Background(), server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get with an open circuit = %v, want ErrCircuitOpen", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("%d requests, want 2: an open circuit must fail fast", hits.Load())
+	}
+}
+
+func TestHTTPClientCancellationIsNotAFailure(t *testing.T) {
+	server, _ := scriptedServer(t, nil, 503)
+	client := NewHTTPClient("test", HTTPClientConfig{
+		MaxRetries:       retries(5),
+		BaseBackoff:      time.Hour,
+		MaxBackoff:       time.Hour,
+		FailureThreshold: 1,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Get(ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get = %v, want the context's deadline", err)
+	}
+	if state, failures := client.Breaker.State(), client.Breaker.ConsecutiveFailures(); state != CircuitClosed || failures != 0 {
+		t.Errorf("circuit %s with %d failures after a cancelled call, want closed with 0", state, failures)
+	}
+}
+
+func TestHTTPClientBackoffHasFullJitter(t *testing.T) {
+	client := NewHTTPClient("test", HTTPClientConfig{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	for attempt, ceiling := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 50; i++ {
+			wait := client.backoff(attempt)
+			if wait < 0 || wait > ceiling {
+				t.Fatalf("backoff(%d) = %s, want within [0, %s]", attempt, wait, ceiling)
+			}
+			seen[wait] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("backoff(%d) always returned the same delay", attempt)
+		}
+	}
+	if wait := client.backoff(100); wait > time.Second {
+		t.Errorf("backoff(100) = %s, want at most MaxBackoff", wait)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", 0, 0},
+		{"5", 5 * time.Second, 5 * time.Second},
+		{"0", 0, 0},
+		{"-3", 0, 0},
+		{"soon", 0, 0},
+		{time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+			t.Errorf("parseRetryAfter(%q) = %s, want between %s and %s", tt.value, got, tt.min, tt.max)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"sync"
+	"time"
+)
+
+// MonitoredProvider records the outcome of every fetch made through Provider
+// so provider health can be reported without scraping logs.
+type MonitoredProvider struct {
+	Provider RateProvider
+
+	mutex  sync.Mutex
+	status models.ProviderStatus
+}
+
+func NewMonitoredProvider(p RateProvider) *MonitoredProvider {
+	return &MonitoredProvider{
+		Provider: p,
+		status:   models.ProviderStatus{Name: p.Name()},
+	}
+}
+
+func (m *MonitoredProvider) Name() string {
+	return m.Provider.Name()
+}
+
+func (m *MonitoredProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	rates, err := m.Provider.GetRates(ctx)
+	if err != nil && ctx.Err() != nil {
+		// Abandoned by the caller, which says nothing about the provider.
+		return rates, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now().Unix()
+	m.status.LastAttemptAt = now
+	if err != nil {
+		m.status.LastErrorAt = now
+		m.status.LastError = err.Error()
+		m.status.ConsecutiveFailures++
+		return rates, err
+	}
+	m.status.LastSuccessAt = now
+	m.status.ConsecutiveFailures = 0
+	m.status.RateCount = len(rates)
+	return rates, nil
+}
+
+// Status returns a snapshot of the recorded outcomes and, for providers
+// fetching over HTTP, the current circuit state.
+func (m *MonitoredProvider) Status() models.ProviderStatus {
+	m.mutex.Lock()
+	status := m.status
+	m.mutex.Unlock()
+
+	if reporter, ok := m.Provider.(CircuitReporter); ok {
+		status.CircuitState = reporter.CircuitState().String()
+	}
+	return status
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"assignment1/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails its first failures fetches and then serves rates,
+// reporting the state of its breaker like an HTTP-backed provider.
+type flakyProvider struct {
+	failures int
+	calls    int
+	rates    map[string]models.Rate
+	breaker  *CircuitBreaker
+}
+
+func (p *flakyProvider) Name() string {
+	return "flaky"
+}
+
+func (p *flakyProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		p.breaker.Failure()
+		return nil, errors.New("upstream unavailable")
+	}
+	p.breaker.Success()
+	return p.rates, nil
+}
+
+func (p *flakyProvider) CircuitState() CircuitState {
+	return p.breaker.State()
+}
+
+func TestMonitoredProviderRecordsFailuresAndRecovery(t *testing.T) {
+	flaky := &flakyProvider{
+		failures: 2,
+		rates:    rateSet("flaky", "EUR", "GBP"),
+		breaker:  &CircuitBreaker{Name: "flaky", FailureThreshold: 2, OpenTimeout: time.Hour},
+	}
+	m := NewMonitoredProvider(flaky)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.GetRates(ctx); err == nil {
+			t.Fatalf("fetch %d succeeded, want the scripted failure", i+1)
+		}
+	}
+	status := m.Status()
+	if status.Name != "flaky" || status.ConsecutiveFailures != 2 || status.LastError != "upstream unavailable" {
+		t.Errorf("status after 2 failures = %+v", status)
+	}
+	if status.LastErrorAt == 0 || status.LastAttemptAt != status.LastErrorAt || status.LastSuccessAt != 0 {
+		t.Errorf("timestamps after 2 failures = attempt %d, error %d, success %d", status.LastAttemptAt, status.LastErrorAt, status.LastSuccessAt)
+	}
+	if status.CircuitState != CircuitOpen.String() {
+		t.Errorf("circuit %q after 2 failures, want %q", status.CircuitState, CircuitOpen)
+	}
+
+	if _, err := m.GetRates(ctx); err != nil {
+		t.Fatalf("fetch after recovery returned error: %v", err)
+	}
+	status = m.Status()
+	if status.ConsecutiveFailures != 0 || status.RateCount != 2 || status.LastSuccessAt == 0 {
+		t.Errorf("status after recovery = %+v", status)
+	}
+	if status.LastError != "upstream unavailable" || status.LastErrorAt == 0 {
+		t.Errorf("recovery cleared the last error: %+v", status)
+	}
+	if status.CircuitState != CircuitClosed.String() {
+		t.Errorf("circuit %q after recovery, want %q", status.CircuitState, CircuitClosed)
+	}
+}
+
+func TestMonitoredProviderIgnoresCancelledFetches(t *testing.T) {
+	flaky := &flakyProvider{failures: 1, breaker: &CircuitBreaker{Name: "flaky"}}
+	m := NewMonitoredProvider(flaky)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := m.GetRates(ctx); err == nil {
+		t.Fatal("fetch succeeded, want the scripted failure")
+	}
+	if status := m.Status(); status.ConsecutiveFailures != 0 || status.LastAttemptAt != 0 {
+		t.Errorf("cancelled fetch was recorded: %+v", status)
+	}
+}
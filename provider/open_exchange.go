@@ -2,39 +2,51 @@ package provider
 
 import (
 	"assignment1/models"
+	"context"
 	"encoding/json"
-	"net/http"
+	"github.com/shopspring/decimal"
 )
 
+const OpenExchangeProviderName = "openexchange"
+
 type OpenExchangeProvider struct {
 	URL     string
 	AppId   string
 	Adapter ProviderAdapter
+	Client  *HTTPClient
 }
 
 type apiResponse struct {
-	Rates map[string]float64 `json:"rates"`
-	Base  string             `json:"base"`
-	Time  int64              `json:"timestamp"`
+	// Decoded straight into decimals so the provider's digits are kept exactly.
+	Rates map[string]decimal.Decimal `json:"rates"`
+	Base  string                     `json:"base"`
+	Time  int64                      `json:"timestamp"`
+}
+
+func (o *OpenExchangeProvider) Name() string {
+	return OpenExchangeProviderName
+}
+
+func (o *OpenExchangeProvider) CircuitState() CircuitState {
+	return o.Client.Breaker.State()
 }
 
-func (o *OpenExchangeProvider) fetchRawRates() (*apiResponse, error) {
-	resp, err := http.Get(o.URL + o.AppId)
+func (o *OpenExchangeProvider) fetchRawRates(ctx context.Context) (*apiResponse, error) {
+	body, err := o.Client.Get(ctx, o.URL+o.AppId)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var data apiResponse
-	err = json.NewDecoder(resp.Body).Decode(&data)
+	err = json.Unmarshal(body, &data)
 	if err != nil {
 		return nil, err
 	}
 	return &data, nil
 }
 
-func (o *OpenExchangeProvider) GetRates() (map[string]models.Rate, error) {
-	data, err := o.fetchRawRates()
+func (o *OpenExchangeProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	data, err := o.fetchRawRates(ctx)
 	if err != nil {
 		return nil, err
 	}
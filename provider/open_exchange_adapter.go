@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// OpenExchangeAdapter stamps rates with the upstream timestamp, when the
+// quotes were published, so their age reflects the quote rather than the
+// fetch. The fetch time is only used when the response carries none.
 type OpenExchangeAdapter struct{}
 
 func (a *OpenExchangeAdapter) Adapt(rawData interface{}) (map[string]models.Rate, error) {
@@ -13,7 +16,10 @@ func (a *OpenExchangeAdapter) Adapt(rawData interface{}) (map[string]models.Rate
 	if !ok {
 		return nil, errors.New("invalid data type for OpenExchangeAdapter")
 	}
-	updateTime := time.Now().Unix()
+	updateTime := data.Time
+	if updateTime == 0 {
+		updateTime = time.Now().Unix()
+	}
 	rates := make(map[string]models.Rate)
 	for code, val := range data.Rates {
 		key := data.Base + "_" + code
@@ -21,6 +27,7 @@ func (a *OpenExchangeAdapter) Adapt(rawData interface{}) (map[string]models.Rate
 			Rate:      val,
 			Base:      data.Base,
 			Target:    code,
+			Provider:  OpenExchangeProviderName,
 			UpdatedAt: updateTime,
 		}
 	}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func TestOpenExchangeAdaptUsesUpstreamTimestamp(t *testing.T) {
+	adapter := &OpenExchangeAdapter{}
+	rates, err := adapter.Adapt(&apiResponse{
+		Base:  "USD",
+		Time:  1718000000,
+		Rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.92")},
+	})
+	if err != nil {
+		t.Fatalf("Adapt returned error: %v", err)
+	}
+	if got := rates["USD_EUR"].UpdatedAt; got != 1718000000 {
+		t.Errorf("USD_EUR stamped %d, want the upstream timestamp 1718000000", got)
+	}
+}
+
+func TestOpenExchangeAdaptFallsBackToNow(t *testing.T) {
+	adapter := &OpenExchangeAdapter{}
+	before := time.Now().Unix()
+	rates, err := adapter.Adapt(&apiResponse{
+		Base:  "USD",
+		Rates: map[string]decimal.Decimal{"EUR": decimal.RequireFromString("0.92")},
+	})
+	if err != nil {
+		t.Fatalf("Adapt returned error: %v", err)
+	}
+	if got := rates["USD_EUR"].UpdatedAt; got < before {
+		t.Errorf("USD_EUR stamped %d, want the fetch time (>= %d) without an upstream timestamp", got, before)
+	}
+}
@@ -1,7 +1,27 @@
 package provider
 
-import "assignment1/models"
+import (
+	"assignment1/models"
+	"context"
+)
 
 type RateProvider interface {
-	GetRates() (map[string]models.Rate, error)
+	// Name identifies the provider in logs and in the Provider field of the
+	// rates it returns.
+	Name() string
+	// GetRates fetches the latest rates; ctx bounds any upstream call.
+	GetRates(ctx context.Context) (map[string]models.Rate, error)
+}
+
+// HistoricalRateProvider is implemented by providers that can also publish
+// past observations, used to backfill the rate history.
+type HistoricalRateProvider interface {
+	Name() string
+	GetHistoricalRates(ctx context.Context) ([]models.Rate, error)
+}
+
+// CircuitReporter is implemented by providers that call their upstream
+// through an HTTPClient, exposing its circuit breaker state.
+type CircuitReporter interface {
+	CircuitState() CircuitState
 }
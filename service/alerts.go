@@ -0,0 +1,249 @@
+package service
+
+import (
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"log"
+	"time"
+)
+
+const (
+	maxAlertWindow      = 30 * 24 * 60 * 60
+	maxAlertDeliveryLog = 100
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// AlertRequest describes an alert to create. Threshold is a rate for above
+// and below alerts and a percentage for change alerts, which also need a
+// Window in seconds.
+type AlertRequest struct {
+	Base        string
+	Target      string
+	Condition   string
+	Threshold   decimal.Decimal
+	Window      int64
+	CallbackURL string
+}
+
+// CreateAlert registers an alert for client. The returned secret signs the
+// alert's webhooks and is not shown again.
+func (rs *RateService) CreateAlert(ctx context.Context, client string, req AlertRequest) (models.CreatedAlertDto, error) {
+	req.Base, req.Target = normalizeCode(req.Base), normalizeCode(req.Target)
+	if err := rs.validateCurrencies(req.Base, req.Target); err != nil {
+		return models.CreatedAlertDto{}, err
+	}
+	if req.Base == req.Target {
+		return models.CreatedAlertDto{}, InvalidInput("target", "base and target must differ")
+	}
+	switch req.Condition {
+	case models.AlertAbove, models.AlertBelow:
+		req.Window = 0
+	case models.AlertChange:
+		if req.Window <= 0 || req.Window > maxAlertWindow {
+			return models.CreatedAlertDto{}, InvalidInput("window", "window must be between 1 second and 30 days for change alerts")
+		}
+	default:
+		return models.CreatedAlertDto{}, InvalidInput("condition", "unsupported condition %q, expected above, below or change", req.Condition)
+	}
+	if !req.Threshold.IsPositive() {
+		return models.CreatedAlertDto{}, InvalidInput("threshold", "threshold must be positive")
+	}
+	if err := rs.callbackPolicy().CheckURL(ctx, req.CallbackURL); err != nil {
+		log.Printf("Alert Error: Rejected callback for %s_%s, error: %v", req.Base, req.Target, err)
+		return models.CreatedAlertDto{}, InvalidInput("callback_url", "%v", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return models.CreatedAlertDto{}, upstream(err, "failed to generate webhook secret")
+	}
+	alert := models.RateAlert{
+		ClientID:    client,
+		Base:        req.Base,
+		Target:      req.Target,
+		Condition:   req.Condition,
+		Threshold:   req.Threshold,
+		Window:      req.Window,
+		CallbackURL: req.CallbackURL,
+		Secret:      hex.EncodeToString(secret),
+	}
+	if result := db.DB.WithContext(ctx).Create(&alert); result.Error != nil {
+		log.Printf("Alert Error: Failed to save alert for %s_%s, error: %v", req.Base, req.Target, result.Error)
+		return models.CreatedAlertDto{}, upstream(result.Error, "failed to save alert for %s_%s", req.Base, req.Target)
+	}
+	log.Printf("Alert: Created alert %d for %s_%s %s %s", alert.ID, alert.Base, alert.Target, alert.Condition, alert.Threshold)
+	return models.CreatedAlertDto{RateAlert: alert, Secret: alert.Secret}, nil
+}
+
+// callbackPolicy returns the webhook sender's policy, or the default one
+// allowing only public addresses.
+func (rs *RateService) callbackPolicy() *CallbackPolicy {
+	if rs.Webhooks != nil && rs.Webhooks.Policy != nil {
+		return rs.Webhooks.Policy
+	}
+	return NewCallbackPolicy(nil)
+}
+
+func (rs *RateService) ListAlerts(ctx context.Context, client string) ([]models.RateAlert, error) {
+	var alerts []models.RateAlert
+	if err := db.DB.WithContext(ctx).Where("client_id = ?", client).Order("id").Find(&alerts).Error; err != nil {
+		log.Printf("Alert Error: Failed to list alerts, error: %v", err)
+		return nil, upstream(err, "failed to list alerts")
+	}
+	return alerts, nil
+}
+
+func (rs *RateService) DeleteAlert(ctx context.Context, client string, id uint) error {
+	result := db.DB.WithContext(ctx).Where("id = ? AND client_id = ?", id, client).Delete(&models.RateAlert{})
+	if result.Error != nil {
+		log.Printf("Alert Error: Failed to delete alert %d, error: %v", id, result.Error)
+		return upstream(result.Error, "failed to delete alert %d", id)
+	}
+	if result.RowsAffected == 0 {
+		return notFound("no alert found with id %d", id)
+	}
+	log.Printf("Alert: Deleted alert %d", id)
+	return nil
+}
+
+// ListAlertDeliveries returns the most recent delivery attempts of an alert,
+// newest first.
+func (rs *RateService) ListAlertDeliveries(ctx context.Context, client string, id uint) ([]models.AlertDelivery, error) {
+	var alert models.RateAlert
+	result := db.DB.WithContext(ctx).Where("id = ? AND client_id = ?", id, client).Limit(1).Find(&alert)
+	if result.Error != nil {
+		log.Printf("Alert Error: Failed to load alert %d, error: %v", id, result.Error)
+		return nil, upstream(result.Error, "failed to load alert %d", id)
+	}
+	if result.RowsAffected == 0 {
+		return nil, notFound("no alert found with id %d", id)
+	}
+
+	var deliveries []models.AlertDelivery
+	if err := db.DB.WithContext(ctx).Where("alert_id = ?", id).Order("id DESC").Limit(maxAlertDeliveryLog).Find(&deliveries).Error; err != nil {
+		log.Printf("Alert Error: Failed to list deliveries of alert %d, error: %v", id, err)
+		return nil, upstream(err, "failed to list deliveries of alert %d", id)
+	}
+	return deliveries, nil
+}
+
+// evaluateAlerts checks every alert against a freshly synced set of rates.
+// An alert fires when its condition starts to hold and re-arms once it stops
+// holding; the triggered flag is flipped with a conditional update so
+// concurrent syncs cannot fire the same crossing twice.
+func (rs *RateService) evaluateAlerts(ctx context.Context, rates map[string]models.Rate) {
+	var alerts []models.RateAlert
+	if err := db.DB.WithContext(ctx).Find(&alerts).Error; err != nil {
+		log.Printf("Alert Error: Failed to load alerts, error: %v", err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	now := time.Now().Unix()
+	fired := 0
+	for _, alert := range alerts {
+		rate, err := rs.resolveSynced(ctx, rates, alert.Base, alert.Target)
+		if err != nil {
+			log.Printf("Alert Error: Cannot resolve %s_%s for alert %d, error: %v", alert.Base, alert.Target, alert.ID, err)
+			continue
+		}
+		event, met, err := rs.checkAlert(ctx, alert, rate, now)
+		if err != nil {
+			log.Printf("Alert Error: Cannot evaluate alert %d, error: %v", alert.ID, err)
+			continue
+		}
+		if met == alert.Triggered {
+			continue
+		}
+
+		updates := map[string]interface{}{"triggered": met}
+		if met {
+			updates["last_triggered_at"] = now
+		}
+		result := db.DB.WithContext(ctx).Model(&models.RateAlert{}).
+			Where("id = ? AND triggered = ?", alert.ID, alert.Triggered).
+			Updates(updates)
+		if result.Error != nil {
+			log.Printf("Alert Error: Failed to update alert %d, error: %v", alert.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			// Another sync got there first.
+			continue
+		}
+		if !met {
+			log.Printf("Alert: Alert %d re-armed", alert.ID)
+			continue
+		}
+
+		fired++
+		log.Printf("Alert: Alert %d fired, %s_%s = %s %s %s", alert.ID, alert.Base, alert.Target, rate.Rate, alert.Condition, alert.Threshold)
+		if rs.Webhooks == nil {
+			log.Printf("Alert Error: No webhook sender configured, alert %d not delivered", alert.ID)
+			continue
+		}
+		// Deliveries outlive the sync, including their retries.
+		go rs.Webhooks.Deliver(context.WithoutCancel(ctx), alert, event)
+	}
+	log.Printf("Alert: Evaluated %d alerts, %d fired", len(alerts), fired)
+}
+
+// newEventID identifies one firing of an alert. The random suffix keeps two
+// crossings within the same second apart.
+func newEventID(alertID uint, now int64) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating event id: %w", err)
+	}
+	return fmt.Sprintf("alert-%d-%d-%s", alertID, now, hex.EncodeToString(suffix)), nil
+}
+
+// checkAlert reports whether alert's condition holds for rate, along with the
+// event to deliver if it does.
+func (rs *RateService) checkAlert(ctx context.Context, alert models.RateAlert, rate models.Rate, now int64) (models.AlertEvent, bool, error) {
+	eventID, err := newEventID(alert.ID, now)
+	if err != nil {
+		return models.AlertEvent{}, false, err
+	}
+	event := models.AlertEvent{
+		EventID:     eventID,
+		AlertID:     alert.ID,
+		Base:        alert.Base,
+		Target:      alert.Target,
+		Condition:   alert.Condition,
+		Threshold:   alert.Threshold,
+		Window:      alert.Window,
+		Rate:        rate.Rate,
+		UpdatedAt:   rate.UpdatedAt,
+		TriggeredAt: now,
+	}
+
+	switch alert.Condition {
+	case models.AlertAbove:
+		return event, rate.Rate.GreaterThan(alert.Threshold), nil
+	case models.AlertBelow:
+		return event, rate.Rate.LessThan(alert.Threshold), nil
+	case models.AlertChange:
+		reference, err := rs.getRateFromHistory(ctx, alert.Base, alert.Target, now-alert.Window)
+		if err != nil {
+			return event, false, err
+		}
+		if reference.Rate.IsZero() {
+			return event, false, nil
+		}
+		change := rate.Rate.Sub(reference.Rate).Div(reference.Rate).Mul(hundred).Round(4)
+		event.Reference = &reference.Rate
+		event.ChangePercent = &change
+		return event, change.Abs().GreaterThanOrEqual(alert.Threshold), nil
+	default:
+		return event, false, fmt.Errorf("unsupported condition %q", alert.Condition)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"assignment1/cache"
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func alertService(webhooks *WebhookSender) *RateService {
+	return &RateService{
+		Cache:              cache.NewInMemoryCache(),
+		Expiry:             time.Hour,
+		GlobalBaseCurrency: "USD",
+		Webhooks:           webhooks,
+	}
+}
+
+func syncedRates(rate string) map[string]models.Rate {
+	return map[string]models.Rate{"USD_EUR": usdRate("EUR", rate)}
+}
+
+func loadAlert(t *testing.T, id uint) models.RateAlert {
+	t.Helper()
+	var alert models.RateAlert
+	if err := db.DB.First(&alert, id).Error; err != nil {
+		t.Fatalf("loading alert %d: %v", id, err)
+	}
+	return alert
+}
+
+// waitForDeliveries waits until n deliveries of the alert were logged.
+func waitForDeliveries(t *testing.T, alertID uint, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(deliveriesOf(t, alertID)) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d deliveries of alert %d", n, alertID)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCreateAlertRejectsInternalCallbacks(t *testing.T) {
+	rs := alertService(NewWebhookSender(time.Second, 1, time.Millisecond, nil))
+	for _, callback := range []string{
+		"http://127.0.0.1:9000/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://192.168.0.10/hook",
+	} {
+		_, err := rs.CreateAlert(context.Background(), "acme", AlertRequest{
+			Base:        "USD",
+			Target:      "EUR",
+			Condition:   models.AlertAbove,
+			Threshold:   decimal.NewFromInt(1),
+			CallbackURL: callback,
+		})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("CreateAlert with callback %s = %v, want invalid input", callback, err)
+		}
+	}
+}
+
+func TestCreateAlertAcceptsAllowListedCallback(t *testing.T) {
+	useTestDB(t)
+	rs := alertService(localSender(1))
+	created, err := rs.CreateAlert(context.Background(), "acme", AlertRequest{
+		Base:        "USD",
+		Target:      "EUR",
+		Condition:   models.AlertAbove,
+		Threshold:   decimal.NewFromInt(1),
+		CallbackURL: "http://127.0.0.1:9000/hook",
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert returned error: %v", err)
+	}
+	if created.Secret == "" || created.ClientID != "acme" {
+		t.Errorf("created alert = %+v, want a secret owned by acme", created)
+	}
+}
+
+func TestEvaluateAlertsFiresOnceAndRearms(t *testing.T) {
+	useTestDB(t)
+	recv := &receiver{statuses: []int{http.StatusOK}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	rs := alertService(localSender(1))
+	alert := models.RateAlert{
+		ClientID:    "acme",
+		Base:        "USD",
+		Target:      "EUR",
+		Condition:   models.AlertAbove,
+		Threshold:   decimal.NewFromInt(1),
+		CallbackURL: server.URL,
+		Secret:      "s3cret",
+	}
+	if err := db.DB.Create(&alert).Error; err != nil {
+		t.Fatalf("creating alert: %v", err)
+	}
+	ctx := context.Background()
+
+	rs.evaluateAlerts(ctx, syncedRates("0.9"))
+	if loadAlert(t, alert.ID).Triggered {
+		t.Fatal("alert triggered below its threshold")
+	}
+
+	rs.evaluateAlerts(ctx, syncedRates("1.1"))
+	waitForDeliveries(t, alert.ID, 1)
+	if fired := loadAlert(t, alert.ID); !fired.Triggered || fired.LastTriggeredAt == 0 {
+		t.Fatalf("alert = %+v after crossing, want triggered", fired)
+	}
+
+	// Still above: the crossing was already reported.
+	rs.evaluateAlerts(ctx, syncedRates("1.2"))
+
+	rs.evaluateAlerts(ctx, syncedRates("0.95"))
+	if loadAlert(t, alert.ID).Triggered {
+		t.Fatal("alert not re-armed after the rate fell back")
+	}
+
+	rs.evaluateAlerts(ctx, syncedRates("1.05"))
+	waitForDeliveries(t, alert.ID, 2)
+	time.Sleep(50 * time.Millisecond)
+	if hits := recv.hits(); hits != 2 {
+		t.Errorf("receiver got %d webhooks, want 2 (one per crossing)", hits)
+	}
+	if deliveries := deliveriesOf(t, alert.ID); len(deliveries) != 2 || deliveries[0].EventID == deliveries[1].EventID {
+		t.Errorf("deliveries = %+v, want two distinct events", deliveries)
+	}
+}
+
+func TestCheckAlertChange(t *testing.T) {
+	useTestDB(t)
+	now := time.Now().Unix()
+	reference := models.RateHistory{Base: "USD", Target: "EUR", Provider: "openexchange", ObservedAt: now - 3600, Rate: decimal.NewFromInt(1)}
+	if err := db.DB.Create(&reference).Error; err != nil {
+		t.Fatalf("creating history: %v", err)
+	}
+
+	rs := alertService(nil)
+	alert := models.RateAlert{ID: 3, Base: "USD", Target: "EUR", Condition: models.AlertChange, Threshold: decimal.RequireFromString("2.5"), Window: 3600}
+	tests := []struct {
+		rate   string
+		met    bool
+		change string
+	}{
+		{"1.03", true, "3"},
+		{"0.97", true, "-3"},
+		{"1.01", false, "1"},
+	}
+	for _, tt := range tests {
+		event, met, err := rs.checkAlert(context.Background(), alert, usdRate("EUR", tt.rate), now)
+		if err != nil {
+			t.Fatalf("checkAlert returned error: %v", err)
+		}
+		if met != tt.met {
+			t.Errorf("rate %s: met = %v, want %v", tt.rate, met, tt.met)
+		}
+		if event.ChangePercent == nil || !event.ChangePercent.Equal(decimal.RequireFromString(tt.change)) {
+			t.Errorf("rate %s: change = %v, want %s%%", tt.rate, event.ChangePercent, tt.change)
+		}
+	}
+}
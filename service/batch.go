@@ -0,0 +1,162 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"log"
+	"time"
+)
+
+const MaxBatchPairs = 200
+
+// GetRates resolves many pairs at once. Every direct pair and every
+// GlobalBaseCurrency leg is fetched with a single cache multi-get, and the
+// legs still missing after that with a single database query. Failures are
+// reported per pair rather than failing the whole batch. Bid and ask use the
+// spreads of client.
+func (rs *RateService) GetRates(ctx context.Context, pairs []models.RatePair, precision currency.Precision, client string) []models.BatchRateResult {
+	log.Printf("API Call: Getting %d rates in batch", len(pairs))
+	pairs = normalizePairs(pairs)
+
+	results := make([]models.BatchRateResult, len(pairs))
+	keys := make([]string, 0, len(pairs)*3)
+	seenKeys := make(map[string]bool)
+	addKey := func(key string) {
+		if !seenKeys[key] {
+			seenKeys[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for i, pair := range pairs {
+		results[i] = models.BatchRateResult{Base: pair.Base, Target: pair.Target}
+		if err := rs.validateCurrencies(pair.Base, pair.Target); err != nil {
+			results[i].Error = err.Error()
+			results[i].ErrorCode = string(KindOf(err))
+			continue
+		}
+		addKey(pair.Base + "_" + pair.Target)
+		for _, code := range rs.legCodes(pair) {
+			addKey(rs.GlobalBaseCurrency + "_" + code)
+		}
+	}
+
+	// Step 1: One cache round trip for everything we might need
+	cached := rs.Cache.GetMany(ctx, keys, rs.Expiry)
+	log.Printf("Batch Cache: Found %d of %d keys", len(cached), len(keys))
+
+	legs := make(map[string]models.Rate)
+	var missing []string
+	seenMissing := make(map[string]bool)
+	for i, pair := range pairs {
+		if results[i].Error != "" {
+			continue
+		}
+		if _, ok := cached[pair.Base+"_"+pair.Target]; ok {
+			continue
+		}
+		for _, code := range rs.legCodes(pair) {
+			if leg, ok := cached[rs.GlobalBaseCurrency+"_"+code]; ok {
+				legs[code] = leg
+			} else if !seenMissing[code] {
+				seenMissing[code] = true
+				missing = append(missing, code)
+			}
+		}
+	}
+
+	// Step 2: One database query for the legs the cache could not serve
+	if len(missing) > 0 {
+		log.Printf("Batch DB Query: Fetching %d %s legs from database", len(missing), rs.GlobalBaseCurrency)
+		var rows []models.Rate
+		result := db.DB.WithContext(ctx).Where("base = ? AND target IN ?", rs.GlobalBaseCurrency, missing).Find(&rows)
+		if result.Error != nil {
+			log.Printf("Batch DB Error: Failed to fetch legs, error: %v", result.Error)
+		}
+		for _, row := range rows {
+			legs[row.Target] = row
+			rs.Cache.Set(ctx, row.Base+"_"+row.Target, row, rs.Expiry)
+		}
+	}
+
+	// Step 3: Resolve each pair from what we collected
+	for i, pair := range pairs {
+		if results[i].Error != "" {
+			continue
+		}
+		rate, err := rs.resolveBatchPair(ctx, pair, cached, legs)
+		if err != nil {
+			// Pairs not reachable through GlobalBaseCurrency may still have a
+			// path through other stored pairs.
+			rate, err = rs.getRateFromGraph(ctx, pair.Base, pair.Target)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			results[i].ErrorCode = string(KindOf(err))
+			continue
+		}
+		dto := rs.newRateDto(rate, RateOptions{Precision: precision, Client: client})
+		results[i].Data = &dto
+	}
+
+	log.Printf("Batch Success: Resolved %d pairs", len(pairs))
+	return results
+}
+
+// legCodes returns the currencies whose GlobalBaseCurrency rate is needed to
+// answer pair.
+func (rs *RateService) legCodes(pair models.RatePair) []string {
+	if pair.Base == rs.GlobalBaseCurrency {
+		return []string{pair.Target}
+	}
+	return []string{pair.Base, pair.Target}
+}
+
+// resolveBatchPair resolves pair like getRate: an active override wins over
+// the cache, and legs pinned by an override win over stored legs.
+func (rs *RateService) resolveBatchPair(ctx context.Context, pair models.RatePair, cached map[string]models.Rate, legs map[string]models.Rate) (models.Rate, error) {
+	if pair.Base == pair.Target {
+		return identityRate(pair.Base, time.Now().Unix()), nil
+	}
+	if rate, ok := rs.activeOverride(pair.Base, pair.Target); ok {
+		return rate, nil
+	}
+	key := pair.Base + "_" + pair.Target
+	if rate, ok := cached[key]; ok {
+		return rate, nil
+	}
+
+	leg := func(code string) (models.Rate, bool) {
+		if rate, ok := rs.activeOverride(rs.GlobalBaseCurrency, code); ok {
+			return rate, true
+		}
+		rate, ok := legs[code]
+		return rate, ok
+	}
+
+	if pair.Base == rs.GlobalBaseCurrency {
+		rate, ok := leg(pair.Target)
+		if !ok {
+			return models.Rate{}, currencyNotSupported(pair.Target)
+		}
+		return rate, nil
+	}
+
+	usdToBase, ok := leg(pair.Base)
+	if !ok {
+		return models.Rate{}, currencyNotSupported(pair.Base)
+	}
+	usdToTarget, ok := leg(pair.Target)
+	if !ok {
+		return models.Rate{}, currencyNotSupported(pair.Target)
+	}
+
+	rate, err := rs.calculateCrossRateFromRates(usdToBase, usdToTarget, pair.Base, pair.Target)
+	if err != nil {
+		return models.Rate{}, err
+	}
+	rs.Cache.Set(ctx, key, rate, rs.Expiry)
+	return rate, nil
+}
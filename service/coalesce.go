@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesce runs fn once for concurrent callers using the same key and shares
+// its result. The shared call runs with the context of the caller that
+// started it; every caller stops waiting when its own context is done, and
+// callers whose shared call failed only because its starter went away retry
+// with their own context.
+func coalesce[T any](ctx context.Context, group *singleflight.Group, key string, fn func(context.Context) (T, error)) (T, bool, error) {
+	var zero T
+	ch := group.DoChan(key, func() (interface{}, error) {
+		return fn(ctx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, false, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			if isContextError(result.Err) && ctx.Err() == nil {
+				value, err := fn(ctx)
+				return value, false, err
+			}
+			return zero, result.Shared, result.Err
+		}
+		return result.Val.(T), result.Shared, nil
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
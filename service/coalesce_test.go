@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingLookup counts its calls. The first call blocks until release is
+// closed or its context is done; later calls return at once.
+type blockingLookup struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingLookup() *blockingLookup {
+	return &blockingLookup{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (l *blockingLookup) lookup(ctx context.Context) (string, error) {
+	if l.calls.Add(1) > 1 {
+		return "retried", nil
+	}
+	close(l.started)
+	select {
+	case <-l.release:
+		return "shared", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestCoalesceCollapsesConcurrentMisses(t *testing.T) {
+	var group singleflight.Group
+	l := newBlockingLookup()
+	ctx := context.Background()
+
+	first := make(chan string, 1)
+	go func() {
+		value, _, _ := coalesce(ctx, &group, "USD_EUR", l.lookup)
+		first <- value
+	}()
+	<-l.started
+
+	const waiters = 10
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	values := make(chan string, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, wasShared, err := coalesce(ctx, &group, "USD_EUR", l.lookup)
+			if err != nil {
+				t.Errorf("waiter returned error: %v", err)
+			}
+			if wasShared {
+				shared.Add(1)
+			}
+			values <- value
+		}()
+	}
+	// Give the waiters time to join the call in flight before releasing it.
+	time.Sleep(20 * time.Millisecond)
+	close(l.release)
+	wg.Wait()
+	close(values)
+
+	if value := <-first; value != "shared" {
+		t.Errorf("first caller got %q, want shared", value)
+	}
+	for value := range values {
+		if value != "shared" {
+			t.Errorf("waiter got %q, want the shared result", value)
+		}
+	}
+	if calls := l.calls.Load(); calls != 1 {
+		t.Errorf("%d lookups for %d concurrent misses, want 1", calls, waiters+1)
+	}
+	if shared.Load() != waiters {
+		t.Errorf("%d waiters reported a shared result, want %d", shared.Load(), waiters)
+	}
+}
+
+func TestCoalesceRetriesWhenStarterIsCancelled(t *testing.T) {
+	var group singleflight.Group
+	l := newBlockingLookup()
+
+	starterCtx, cancel := context.WithCancel(context.Background())
+	starterErr := make(chan error, 1)
+	go func() {
+		_, _, err := coalesce(starterCtx, &group, "USD_EUR", l.lookup)
+		starterErr <- err
+	}()
+	<-l.started
+
+	waiter := make(chan string, 1)
+	go func() {
+		value, _, err := coalesce(context.Background(), &group, "USD_EUR", l.lookup)
+		if err != nil {
+			t.Errorf("waiter returned error: %v", err)
+		}
+		waiter <- value
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-starterErr; err != context.Canceled {
+		t.Errorf("starter returned %v, want context.Canceled", err)
+	}
+	if value := <-waiter; value != "retried" {
+		t.Errorf("waiter got %q, want its own retried lookup", value)
+	}
+	if calls := l.calls.Load(); calls != 2 {
+		t.Errorf("%d lookups, want the shared one and the waiter's retry", calls)
+	}
+}
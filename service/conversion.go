@@ -0,0 +1,52 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/models"
+	"assignment1/utility"
+	"context"
+	"github.com/shopspring/decimal"
+	"log"
+)
+
+// Convert converts amount from one currency to another using the current rate
+// and rounds the result to the target currency's ISO 4217 minor units. An
+// empty mode falls back to the service's configured RoundingMode.
+func (rs *RateService) Convert(ctx context.Context, from string, to string, amount decimal.Decimal, mode utility.RoundingMode) (models.ConversionDto, error) {
+	from, to = normalizeCode(from), normalizeCode(to)
+	log.Printf("API Call: Converting %s %s to %s", amount, from, to)
+
+	if mode == "" {
+		mode = rs.RoundingMode
+	}
+	if mode == "" {
+		mode = utility.RoundHalfUp
+	}
+
+	if err := rs.validateCurrencies(from, to); err != nil {
+		return models.ConversionDto{}, err
+	}
+
+	rate, err := rs.getRate(ctx, from, to)
+	if err != nil {
+		log.Printf("Conversion Error: Rate not found for %s_%s, error: %v", from, to, err)
+		return models.ConversionDto{}, err
+	}
+
+	converted := amount.Mul(rate.Rate)
+	places := currency.MinorUnits(to)
+	result := rs.Precision.RoundAmount(to, converted, mode)
+
+	log.Printf("Conversion Success: %s %s = %s %s at rate %s", amount, from, converted, to, rate.Rate)
+	return models.ConversionDto{
+		From:            from,
+		To:              to,
+		Amount:          amount,
+		Rate:            rate.Rate,
+		ConvertedAmount: converted,
+		Result:          result.StringFixed(int32(places)),
+		MinorUnits:      places,
+		RoundingMode:    string(mode),
+		UpdatedAt:       rate.UpdatedAt,
+	}, nil
+}
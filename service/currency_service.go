@@ -0,0 +1,73 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"log"
+)
+
+type CurrencyService struct {
+	Registry           *currency.Registry
+	GlobalBaseCurrency string
+}
+
+type CurrencyFilter struct {
+	ActiveOnly    bool
+	SupportedOnly bool
+}
+
+func (cs *CurrencyService) ListCurrencies(ctx context.Context, filter CurrencyFilter) ([]models.CurrencyDto, error) {
+	log.Printf("API Call: Listing currencies (active_only=%t, supported_only=%t)", filter.ActiveOnly, filter.SupportedOnly)
+
+	supported, err := cs.supportedCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]models.CurrencyDto, 0)
+	for _, c := range cs.Registry.List() {
+		if filter.ActiveOnly && !c.Active {
+			continue
+		}
+		if filter.SupportedOnly && !supported[c.Code] {
+			continue
+		}
+		currencies = append(currencies, models.CurrencyDto{
+			Code:        c.Code,
+			Name:        c.Name,
+			NumericCode: c.NumericCode,
+			MinorUnits:  c.MinorUnits,
+			Symbol:      c.Symbol,
+			Countries:   c.Countries,
+			Active:      c.Active,
+			ISO:         c.ISO,
+			Supported:   supported[c.Code],
+		})
+	}
+
+	log.Printf("Currencies: Returning %d of %d catalog entries", len(currencies), len(cs.Registry.List()))
+	return currencies, nil
+}
+
+// supportedCodes returns the codes we hold a GlobalBaseCurrency rate for,
+// i.e. everything GetRate can answer.
+func (cs *CurrencyService) supportedCodes(ctx context.Context) (map[string]bool, error) {
+	var targets []string
+	result := db.DB.WithContext(ctx).Model(&models.Rate{}).
+		Where("base = ?", cs.GlobalBaseCurrency).
+		Distinct().
+		Pluck("target", &targets)
+	if result.Error != nil {
+		log.Printf("DB Error: Failed to load supported currencies, error: %v", result.Error)
+		return nil, upstream(result.Error, "failed to load supported currencies")
+	}
+
+	supported := make(map[string]bool, len(targets)+1)
+	supported[cs.GlobalBaseCurrency] = true
+	for _, target := range targets {
+		supported[target] = true
+	}
+	return supported, nil
+}
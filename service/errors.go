@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrorKind classifies the errors returned by the service so the REST and
+// gRPC layers can map them to status codes. Kinds are errors themselves and
+// are matched with errors.Is, e.g. errors.Is(err, ErrRateUnavailable).
+type ErrorKind string
+
+const (
+	ErrInvalidInput    ErrorKind = "INVALID_INPUT"
+	ErrUnknownCurrency ErrorKind = "UNKNOWN_CURRENCY"
+	ErrRateUnavailable ErrorKind = "RATE_UNAVAILABLE"
+	ErrNotFound        ErrorKind = "NOT_FOUND"
+	ErrStaleRate       ErrorKind = "STALE_RATE"
+	// ErrUpstream covers failures of the providers, database and cache the
+	// service depends on.
+	ErrUpstream ErrorKind = "UPSTREAM_FAILURE"
+)
+
+func (k ErrorKind) Error() string {
+	return string(k)
+}
+
+// KindOf returns the kind of err, or "" when err was not classified.
+func KindOf(err error) ErrorKind {
+	var kind ErrorKind
+	if errors.As(err, &kind) {
+		return kind
+	}
+	return ""
+}
+
+// Error is a classified service error. Message is safe to return to clients,
+// Metadata holds structured context such as the pair or the invalid field,
+// and Err is the underlying cause, which is only logged.
+type Error struct {
+	Kind     ErrorKind
+	Message  string
+	Metadata map[string]string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// StaleRateError is returned when a rate is older than the caller's MaxAge.
+type StaleRateError struct {
+	Base   string
+	Target string
+	Age    time.Duration
+	MaxAge time.Duration
+}
+
+func (e *StaleRateError) Error() string {
+	return fmt.Sprintf("rate %s_%s is %s old, older than the requested max age of %s", e.Base, e.Target, e.Age, e.MaxAge)
+}
+
+func (e *StaleRateError) Unwrap() error {
+	return ErrStaleRate
+}
+
+// ErrorMetadata returns the structured context attached to err, if any.
+func ErrorMetadata(err error) map[string]string {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Metadata
+	}
+	var staleErr *StaleRateError
+	if errors.As(err, &staleErr) {
+		return map[string]string{
+			"base":            staleErr.Base,
+			"target":          staleErr.Target,
+			"age_seconds":     strconv.FormatInt(int64(staleErr.Age.Seconds()), 10),
+			"max_age_seconds": strconv.FormatInt(int64(staleErr.MaxAge.Seconds()), 10),
+		}
+	}
+	return nil
+}
+
+// InvalidInput is the error for a request whose field failed validation.
+// Handlers also use it for checks they make before calling the service, so
+// REST and gRPC report those the same way.
+func InvalidInput(field, format string, args ...interface{}) error {
+	return &Error{Kind: ErrInvalidInput, Message: fmt.Sprintf(format, args...), Metadata: map[string]string{"field": field}}
+}
+
+func unknownCurrency(code string) error {
+	return &Error{Kind: ErrUnknownCurrency, Message: fmt.Sprintf("unknown currency code %s", code), Metadata: map[string]string{"currency": code}}
+}
+
+func currencyNotSupported(code string) error {
+	return &Error{Kind: ErrRateUnavailable, Message: fmt.Sprintf("provided currency %s is currently not supported", code), Metadata: map[string]string{"currency": code}}
+}
+
+func rateUnavailable(base, target, format string, args ...interface{}) error {
+	return &Error{Kind: ErrRateUnavailable, Message: fmt.Sprintf(format, args...), Metadata: map[string]string{"base": base, "target": target}}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func upstream(err error, format string, args ...interface{}) error {
+	return &Error{Kind: ErrUpstream, Message: fmt.Sprintf(format, args...), Err: err}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
+	"log"
+	"sync"
+	"time"
+)
+
+// OverrideProviderName is reported as the provider of rates pinned by an
+// operator override.
+const OverrideProviderName = "override"
+
+// overrideSet is the in-memory snapshot of active overrides, keyed by pair.
+// It is reloaded from Postgres on every sync so overrides written by another
+// instance are picked up without a restart.
+type overrideSet struct {
+	mutex     sync.RWMutex
+	overrides map[string]models.RateOverride
+	// expiries tracks the expiry already scheduled per pair so reloading the
+	// snapshot does not start duplicate timers.
+	expiries map[string]int64
+}
+
+// SetOverride pins base/target to rate until expiresAt (epoch seconds),
+// replacing any existing override for the pair. The override is applied to
+// the cache and database immediately rather than on the next sync.
+func (rs *RateService) SetOverride(ctx context.Context, base, target string, rate decimal.Decimal, reason string, expiresAt int64) (models.RateOverride, error) {
+	base, target = normalizeCode(base), normalizeCode(target)
+	if err := rs.validateCurrencies(base, target); err != nil {
+		return models.RateOverride{}, err
+	}
+	if base == target {
+		return models.RateOverride{}, InvalidInput("target", "base and target must differ")
+	}
+	if !rate.IsPositive() {
+		return models.RateOverride{}, InvalidInput("rate", "rate must be positive")
+	}
+	if reason == "" {
+		return models.RateOverride{}, InvalidInput("reason", "reason is required")
+	}
+	now := time.Now().Unix()
+	if expiresAt <= now {
+		return models.RateOverride{}, InvalidInput("expires_at", "expiry must be in the future")
+	}
+
+	override := models.RateOverride{
+		Base:      base,
+		Target:    target,
+		Rate:      rate,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}
+	result := db.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "target"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "reason", "expires_at", "updated_at"}),
+	}).Create(&override)
+	if result.Error != nil {
+		log.Printf("Override Error: Failed to save override for %s_%s, error: %v", base, target, result.Error)
+		return models.RateOverride{}, upstream(result.Error, "failed to save override for %s_%s", base, target)
+	}
+	// Reload so an updated override reports its original ID and CreatedAt.
+	db.DB.WithContext(ctx).Where("base = ? AND target = ?", base, target).First(&override)
+	log.Printf("Override: Pinned %s_%s = %s until %d (%s)", base, target, rate, expiresAt, reason)
+
+	rs.overrides.put(override)
+	rs.scheduleOverrideExpiry(override)
+
+	pinned := map[string]models.Rate{
+		base + "_" + target: override.ToRate(OverrideProviderName, now),
+	}
+	rs.invalidateDerived(ctx, base, target)
+	rs.syncToCache(ctx, pinned)
+	rs.syncToDB(ctx, pinned)
+	rs.publishChanges(ctx, pinned)
+
+	return override, nil
+}
+
+// ListOverrides returns overrides ordered by pair. Expired overrides are kept
+// in the table for reference and only returned when includeExpired is set.
+func (rs *RateService) ListOverrides(ctx context.Context, includeExpired bool) ([]models.RateOverride, error) {
+	query := db.DB.WithContext(ctx).Order("base, target")
+	if !includeExpired {
+		query = query.Where("expires_at > ?", time.Now().Unix())
+	}
+	var overrides []models.RateOverride
+	if err := query.Find(&overrides).Error; err != nil {
+		log.Printf("Override Error: Failed to list overrides, error: %v", err)
+		return nil, upstream(err, "failed to list overrides")
+	}
+	return overrides, nil
+}
+
+// DeleteOverride removes the override for base/target and restores provider
+// data for the pair.
+func (rs *RateService) DeleteOverride(ctx context.Context, base, target string) error {
+	base, target = normalizeCode(base), normalizeCode(target)
+	result := db.DB.WithContext(ctx).Where("base = ? AND target = ?", base, target).Delete(&models.RateOverride{})
+	if result.Error != nil {
+		log.Printf("Override Error: Failed to delete override for %s_%s, error: %v", base, target, result.Error)
+		return upstream(result.Error, "failed to delete override for %s_%s", base, target)
+	}
+	if result.RowsAffected == 0 {
+		return notFound("no override found for %s_%s", base, target)
+	}
+	log.Printf("Override: Removed override for %s_%s", base, target)
+
+	rs.overrides.remove(base + "_" + target)
+	rs.releaseOverride(ctx, base, target)
+	return nil
+}
+
+// LoadOverrides refreshes the active override snapshot from the database.
+func (rs *RateService) LoadOverrides(ctx context.Context) {
+	var overrides []models.RateOverride
+	if err := db.DB.WithContext(ctx).Where("expires_at > ?", time.Now().Unix()).Find(&overrides).Error; err != nil {
+		log.Printf("Override Error: Failed to load overrides, error: %v", err)
+		return
+	}
+	rs.overrides.replace(overrides)
+	for _, override := range overrides {
+		rs.scheduleOverrideExpiry(override)
+	}
+	log.Printf("Override: Loaded %d active overrides", len(overrides))
+}
+
+// activeOverride returns the pinned rate for base/target if an unexpired
+// override exists. A pinned rate is current until it expires, so it is
+// reported as updated now.
+func (rs *RateService) activeOverride(base, target string) (models.Rate, bool) {
+	now := time.Now().Unix()
+	override, ok := rs.overrides.get(base + "_" + target)
+	if !ok || override.ExpiresAt <= now {
+		return models.Rate{}, false
+	}
+	return override.ToRate(OverrideProviderName, now), true
+}
+
+// applyOverrides replaces provider rates with active overrides, adding pairs
+// the provider does not quote.
+func (rs *RateService) applyOverrides(rates map[string]models.Rate) {
+	now := time.Now().Unix()
+	for key, override := range rs.overrides.active(now) {
+		rates[key] = override.ToRate(OverrideProviderName, now)
+		log.Printf("Override: Applying %s = %s over provider data", key, override.Rate)
+	}
+}
+
+func (rs *RateService) scheduleOverrideExpiry(override models.RateOverride) {
+	key := override.Base + "_" + override.Target
+	if !rs.overrides.markScheduled(key, override.ExpiresAt) {
+		return
+	}
+	time.AfterFunc(time.Until(time.Unix(override.ExpiresAt, 0)), func() {
+		if _, ok := rs.activeOverride(override.Base, override.Target); ok {
+			// Extended since this timer was scheduled.
+			return
+		}
+		log.Printf("Override: Override for %s expired", key)
+		rs.overrides.remove(key)
+		rs.releaseOverride(context.Background(), override.Base, override.Target)
+	})
+}
+
+// releaseOverride drops the pinned rate and the rates derived from it from
+// the cache and resyncs so the pair falls back to provider data. Only pairs on
+// the global base are stored by the provider, so other pinned pairs are
+// removed from the rates table.
+func (rs *RateService) releaseOverride(ctx context.Context, base, target string) {
+	rs.invalidateDerived(ctx, base, target)
+	if base != rs.GlobalBaseCurrency {
+		db.DB.WithContext(ctx).Where("base = ? AND target = ? AND provider = ?", base, target, OverrideProviderName).Delete(&models.Rate{})
+		rs.invalidateGraph()
+		rs.publishChanges(ctx, nil)
+		return
+	}
+	go func() {
+		rs.SyncNow()
+		// Drop cross rates derived from the pinned leg between the release and
+		// the sync restoring provider data.
+		rs.invalidateDerived(context.Background(), base, target)
+	}()
+}
+
+// invalidateDerived drops every cached pair involving one of codes, so cross
+// rates derived through a pinned or released leg are recomputed instead of
+// being served until they expire. Every cross rate goes through the global
+// base, so it is skipped: pinning USD_ARS only affects pairs involving ARS.
+func (rs *RateService) invalidateDerived(ctx context.Context, codes ...string) {
+	registry := rs.Currencies
+	if registry == nil {
+		registry = currency.Default()
+	}
+	catalog := registry.List()
+	keys := make([]string, 0, 2*len(codes)*len(catalog))
+	for _, code := range codes {
+		if code == rs.GlobalBaseCurrency {
+			continue
+		}
+		for _, other := range catalog {
+			keys = append(keys, code+"_"+other.Code, other.Code+"_"+code)
+		}
+	}
+	rs.Cache.Delete(ctx, keys...)
+	log.Printf("Override: Invalidated cached rates involving %v", codes)
+}
+
+func (s *overrideSet) get(key string) (models.RateOverride, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	override, ok := s.overrides[key]
+	return override, ok
+}
+
+func (s *overrideSet) put(override models.RateOverride) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.overrides == nil {
+		s.overrides = make(map[string]models.RateOverride)
+	}
+	s.overrides[override.Base+"_"+override.Target] = override
+}
+
+func (s *overrideSet) remove(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.overrides, key)
+	delete(s.expiries, key)
+}
+
+func (s *overrideSet) replace(overrides []models.RateOverride) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.overrides = make(map[string]models.RateOverride, len(overrides))
+	for _, override := range overrides {
+		s.overrides[override.Base+"_"+override.Target] = override
+	}
+}
+
+func (s *overrideSet) active(now int64) map[string]models.RateOverride {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	active := make(map[string]models.RateOverride, len(s.overrides))
+	for key, override := range s.overrides {
+		if override.ExpiresAt > now {
+			active[key] = override
+		}
+	}
+	return active
+}
+
+// markScheduled records that an expiry timer is running for key and reports
+// whether a new timer is needed.
+func (s *overrideSet) markScheduled(key string, expiresAt int64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.expiries == nil {
+		s.expiries = make(map[string]int64)
+	}
+	if s.expiries[key] == expiresAt {
+		return false
+	}
+	s.expiries[key] = expiresAt
+	return true
+}
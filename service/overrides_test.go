@@ -0,0 +1,80 @@
+package service
+
+import (
+	"assignment1/cache"
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func pinnedService(t *testing.T, base, target, rate string) *RateService {
+	t.Helper()
+	rs := &RateService{
+		Cache:              cache.NewInMemoryCache(),
+		Expiry:             time.Hour,
+		GlobalBaseCurrency: "USD",
+	}
+	rs.overrides.put(models.RateOverride{
+		Base:      base,
+		Target:    target,
+		Rate:      decimal.RequireFromString(rate),
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	return rs
+}
+
+func usdRate(target, rate string) models.Rate {
+	return models.Rate{Base: "USD", Target: target, Rate: decimal.RequireFromString(rate), Provider: "openexchange"}
+}
+
+func TestResolveBatchPairPrefersOverrides(t *testing.T) {
+	rs := pinnedService(t, "EUR", "ARS", "1300")
+	cached := map[string]models.Rate{
+		"EUR_ARS": {Base: "EUR", Target: "ARS", Rate: decimal.RequireFromString("1000")},
+	}
+	rate, err := rs.resolveBatchPair(context.Background(), models.RatePair{Base: "EUR", Target: "ARS"}, cached, nil)
+	if err != nil {
+		t.Fatalf("resolveBatchPair returned error: %v", err)
+	}
+	if rate.Provider != OverrideProviderName || !rate.Rate.Equal(decimal.NewFromInt(1300)) {
+		t.Errorf("EUR_ARS = %s from %s, want the pinned 1300", rate.Rate, rate.Provider)
+	}
+}
+
+func TestResolveBatchPairUsesPinnedLegs(t *testing.T) {
+	rs := pinnedService(t, "USD", "ARS", "1200")
+	legs := map[string]models.Rate{
+		"EUR": usdRate("EUR", "0.8"),
+		"ARS": usdRate("ARS", "1000"),
+	}
+	rate, err := rs.resolveBatchPair(context.Background(), models.RatePair{Base: "EUR", Target: "ARS"}, nil, legs)
+	if err != nil {
+		t.Fatalf("resolveBatchPair returned error: %v", err)
+	}
+	if want := decimal.NewFromInt(1500); !rate.Rate.Equal(want) {
+		t.Errorf("EUR_ARS = %s, want %s derived from the pinned USD_ARS", rate.Rate, want)
+	}
+}
+
+func TestInvalidateDerivedDropsPairsInvolvingThePinnedLeg(t *testing.T) {
+	rs := pinnedService(t, "USD", "ARS", "1200")
+	ctx := context.Background()
+	for _, key := range []string{"EUR_ARS", "ARS_EUR", "USD_ARS", "EUR_GBP", "USD_EUR"} {
+		rs.Cache.Set(ctx, key, models.Rate{Rate: decimal.NewFromInt(1)}, rs.Expiry)
+	}
+
+	rs.invalidateDerived(ctx, "USD", "ARS")
+
+	for _, key := range []string{"EUR_ARS", "ARS_EUR", "USD_ARS"} {
+		if _, ok := rs.Cache.Get(ctx, key, rs.Expiry); ok {
+			t.Errorf("%s still cached after invalidating ARS", key)
+		}
+	}
+	for _, key := range []string{"EUR_GBP", "USD_EUR"} {
+		if _, ok := rs.Cache.Get(ctx, key, rs.Expiry); !ok {
+			t.Errorf("%s was invalidated although it does not involve ARS", key)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"golang.org/x/sync/singleflight"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPathHops bounds how many stored pairs a derived rate may chain.
+const maxPathHops = 4
+
+// IdentityProviderName is reported as the provider of a currency quoted
+// against itself.
+const IdentityProviderName = "identity"
+
+// rateGraph links currencies by every stored pair. Each pair is an edge in
+// both directions, so a quote stored as EUR_ARS also answers ARS_EUR.
+type rateGraph struct {
+	edges    map[string][]graphEdge
+	loadedAt time.Time
+}
+
+type graphEdge struct {
+	to   string
+	rate models.Rate
+	// inverse is set when the edge runs against the stored quote.
+	inverse bool
+}
+
+// graphState holds the graph snapshot shared by lookups. It is rebuilt from
+// the rates table when older than the cache expiry or after a sync.
+type graphState struct {
+	mutex sync.Mutex
+	graph *rateGraph
+	// version counts invalidations so a load that raced one is not kept.
+	version uint64
+	// loads collapses concurrent rebuilds into one query.
+	loads singleflight.Group
+}
+
+// identityRate quotes code against itself. The rate is exact and holds at
+// any time, so it is stamped with at and never reported stale.
+func identityRate(code string, at int64) models.Rate {
+	return models.Rate{
+		Base:      code,
+		Target:    code,
+		Rate:      decimal.NewFromInt(1),
+		Provider:  IdentityProviderName,
+		UpdatedAt: at,
+		Path:      []string{code},
+	}
+}
+
+func newRateGraph(rows []models.Rate) *rateGraph {
+	g := &rateGraph{
+		edges:    make(map[string][]graphEdge),
+		loadedAt: time.Now(),
+	}
+	for _, row := range rows {
+		if row.Rate.IsZero() || row.Base == row.Target {
+			continue
+		}
+		g.edges[row.Base] = append(g.edges[row.Base], graphEdge{to: row.Target, rate: row})
+		g.edges[row.Target] = append(g.edges[row.Target], graphEdge{to: row.Base, rate: row, inverse: true})
+	}
+	return g
+}
+
+// pathStep records how a currency was reached during the search.
+type pathStep struct {
+	from string
+	edge graphEdge
+	// oldest is the UpdatedAt of the oldest pair on the path so far.
+	oldest int64
+}
+
+// shortestPath returns the edges of the path from base to target with the
+// fewest hops. Among equally short paths the freshest one wins, i.e. the one
+// whose oldest pair was updated most recently.
+func (g *rateGraph) shortestPath(base, target string) ([]graphEdge, bool) {
+	reached := map[string]pathStep{base: {oldest: int64(^uint64(0) >> 1)}}
+	frontier := []string{base}
+	for hop := 0; hop < maxPathHops && len(frontier) > 0; hop++ {
+		next := make(map[string]pathStep)
+		for _, code := range frontier {
+			from := reached[code]
+			for _, edge := range g.edges[code] {
+				if _, seen := reached[edge.to]; seen {
+					continue
+				}
+				oldest := min(from.oldest, edge.rate.UpdatedAt)
+				if step, ok := next[edge.to]; !ok || oldest > step.oldest {
+					next[edge.to] = pathStep{from: code, edge: edge, oldest: oldest}
+				}
+			}
+		}
+		frontier = frontier[:0]
+		for code, step := range next {
+			reached[code] = step
+			frontier = append(frontier, code)
+		}
+		if _, ok := reached[target]; ok {
+			break
+		}
+	}
+
+	if _, ok := reached[target]; !ok {
+		return nil, false
+	}
+	var path []graphEdge
+	for code := target; code != base; code = reached[code].from {
+		path = append([]graphEdge{reached[code].edge}, path...)
+	}
+	return path, true
+}
+
+// rateAlongPath multiplies the quotes along path. Inverted legs are collected
+// in the denominator so the result needs a single rounded division.
+func rateAlongPath(base, target string, path []graphEdge) (models.Rate, error) {
+	numerator := decimal.NewFromInt(1)
+	denominator := decimal.NewFromInt(1)
+	codes := []string{base}
+	var providers []string
+	var updatedAt int64
+	for _, edge := range path {
+		if edge.inverse {
+			denominator = denominator.Mul(edge.rate.Rate)
+		} else {
+			numerator = numerator.Mul(edge.rate.Rate)
+		}
+		codes = append(codes, edge.to)
+		if !slices.Contains(providers, edge.rate.Provider) {
+			providers = append(providers, edge.rate.Provider)
+		}
+		if updatedAt == 0 || edge.rate.UpdatedAt < updatedAt {
+			updatedAt = edge.rate.UpdatedAt
+		}
+	}
+	if denominator.IsZero() {
+		return models.Rate{}, rateUnavailable(base, target, "cannot derive %s_%s through a zero rate", base, target)
+	}
+
+	return models.Rate{
+		Base:      base,
+		Target:    target,
+		Rate:      numerator.DivRound(denominator, crossRatePrecision),
+		Provider:  strings.Join(providers, "+"),
+		UpdatedAt: updatedAt,
+		Path:      codes,
+	}, nil
+}
+
+// getRateFromGraph resolves base/target over every stored pair, through any
+// intermediaries, using the shortest and then freshest path.
+func (rs *RateService) getRateFromGraph(ctx context.Context, base, target string) (models.Rate, error) {
+	graph, err := rs.currencyGraph(ctx)
+	if err != nil {
+		return models.Rate{}, err
+	}
+
+	for _, code := range []string{base, target} {
+		if len(graph.edges[code]) == 0 {
+			log.Printf("Graph MISS: No stored pair involves %s", code)
+			return models.Rate{}, currencyNotSupported(code)
+		}
+	}
+
+	path, ok := graph.shortestPath(base, target)
+	if !ok {
+		log.Printf("Graph MISS: No path from %s to %s within %d hops", base, target, maxPathHops)
+		return models.Rate{}, rateUnavailable(base, target, "no rate path found from %s to %s", base, target)
+	}
+
+	rate, err := rateAlongPath(base, target, path)
+	if err != nil {
+		log.Printf("Graph Error: %v", err)
+		return models.Rate{}, err
+	}
+	log.Printf("Graph HIT: Resolved %s_%s = %s via %s", base, target, rate.Rate, strings.Join(rate.Path, "->"))
+	return rate, nil
+}
+
+// currencyGraph returns the cached graph, rebuilding it when stale. The rates
+// table is read without holding the mutex, and concurrent rebuilds share one
+// load.
+func (rs *RateService) currencyGraph(ctx context.Context) (*rateGraph, error) {
+	rs.graph.mutex.Lock()
+	graph := rs.graph.graph
+	rs.graph.mutex.Unlock()
+	if graph != nil && time.Since(graph.loadedAt) < rs.Expiry {
+		return graph, nil
+	}
+
+	graph, shared, err := coalesce(ctx, &rs.graph.loads, "graph", rs.loadGraph)
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		log.Printf("Coalesced: Shared in-flight graph load")
+	}
+	return graph, nil
+}
+
+func (rs *RateService) loadGraph(ctx context.Context) (*rateGraph, error) {
+	rs.graph.mutex.Lock()
+	version := rs.graph.version
+	rs.graph.mutex.Unlock()
+
+	var rows []models.Rate
+	if err := db.DB.WithContext(ctx).Find(&rows).Error; err != nil {
+		log.Printf("Graph Error: Failed to load rates, error: %v", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, upstream(err, "failed to load rates")
+	}
+	graph := newRateGraph(rows)
+	log.Printf("Graph: Built currency graph from %d pairs", len(rows))
+
+	rs.graph.mutex.Lock()
+	defer rs.graph.mutex.Unlock()
+	if rs.graph.version == version {
+		rs.graph.graph = graph
+	}
+	return graph, nil
+}
+
+// invalidateGraph forces the next lookup to rebuild the graph, after the
+// rates table has changed.
+func (rs *RateService) invalidateGraph() {
+	rs.graph.mutex.Lock()
+	defer rs.graph.mutex.Unlock()
+	rs.graph.graph = nil
+	rs.graph.version++
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/models"
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"strings"
+	"testing"
+	"time"
+)
+
+func storedRate(provider, base, target, rate string, updatedAt int64) models.Rate {
+	return models.Rate{Base: base, Target: target, Rate: decimal.RequireFromString(rate), Provider: provider, UpdatedAt: updatedAt}
+}
+
+func testGraph() *rateGraph {
+	return newRateGraph([]models.Rate{
+		storedRate("a", "USD", "EUR", "0.8", 100),
+		storedRate("a", "USD", "GBP", "0.75", 100),
+		storedRate("b", "EUR", "JPY", "160", 50),
+		storedRate("c", "GBP", "JPY", "190", 90),
+		// A chain of five pairs, one longer than maxPathHops.
+		storedRate("d", "CHF", "SEK", "11", 100),
+		storedRate("d", "SEK", "NOK", "1", 100),
+		storedRate("d", "NOK", "DKK", "0.65", 100),
+		storedRate("d", "DKK", "PLN", "0.6", 100),
+		storedRate("d", "PLN", "HUF", "90", 100),
+		storedRate("e", "XAU", "XAG", "80", 100),
+	})
+}
+
+func pathCodes(base string, path []graphEdge) string {
+	codes := []string{base}
+	for _, edge := range path {
+		codes = append(codes, edge.to)
+	}
+	return strings.Join(codes, "->")
+}
+
+func TestShortestPath(t *testing.T) {
+	g := testGraph()
+	tests := []struct {
+		name     string
+		base     string
+		target   string
+		want     string
+		inverses int
+	}{
+		{"stored pair", "USD", "EUR", "USD->EUR", 0},
+		{"inverse edge", "EUR", "USD", "EUR->USD", 1},
+		{"freshest of two equal paths", "USD", "JPY", "USD->GBP->JPY", 0},
+		{"freshest path against the quotes", "JPY", "USD", "JPY->GBP->USD", 2},
+		{"fewer hops beat fresher ones", "EUR", "GBP", "EUR->USD->GBP", 1},
+		{"four hops", "CHF", "PLN", "CHF->SEK->NOK->DKK->PLN", 0},
+		{"four hops inverted", "PLN", "CHF", "PLN->DKK->NOK->SEK->CHF", 4},
+		{"beyond four hops", "CHF", "HUF", "", 0},
+		{"disconnected", "USD", "XAU", "", 0},
+		{"unknown currency", "USD", "ZZZ", "", 0},
+	}
+	for _, tt := range tests {
+		path, ok := g.shortestPath(tt.base, tt.target)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s: found %s, want no path", tt.name, pathCodes(tt.base, path))
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: no path, want %s", tt.name, tt.want)
+			continue
+		}
+		if got := pathCodes(tt.base, path); got != tt.want {
+			t.Errorf("%s: path %s, want %s", tt.name, got, tt.want)
+		}
+		inverses := 0
+		for _, edge := range path {
+			if edge.inverse {
+				inverses++
+			}
+		}
+		if inverses != tt.inverses {
+			t.Errorf("%s: %d inverse edges, want %d", tt.name, inverses, tt.inverses)
+		}
+	}
+}
+
+func TestRateAlongPath(t *testing.T) {
+	g := testGraph()
+	tests := []struct {
+		name      string
+		base      string
+		target    string
+		rate      string
+		provider  string
+		updatedAt int64
+	}{
+		{"stored pair", "USD", "EUR", "0.8", "a", 100},
+		{"inverse edge", "EUR", "USD", "1.25", "a", 100},
+		{"forward legs", "USD", "JPY", "142.5", "a+c", 90},
+		{"inverse legs share one division", "JPY", "USD", "0.00701754385964912281", "c+a", 90},
+		{"mixed legs", "EUR", "GBP", "0.9375", "a", 100},
+		{"four hops", "CHF", "PLN", "4.29", "d", 100},
+	}
+	for _, tt := range tests {
+		path, ok := g.shortestPath(tt.base, tt.target)
+		if !ok {
+			t.Errorf("%s: no path from %s to %s", tt.name, tt.base, tt.target)
+			continue
+		}
+		rate, err := rateAlongPath(tt.base, tt.target, path)
+		if err != nil {
+			t.Errorf("%s: rateAlongPath returned error: %v", tt.name, err)
+			continue
+		}
+		if !rate.Rate.Equal(decimal.RequireFromString(tt.rate)) || rate.Provider != tt.provider || rate.UpdatedAt != tt.updatedAt {
+			t.Errorf("%s: %s from %s at %d, want %s from %s at %d", tt.name, rate.Rate, rate.Provider, rate.UpdatedAt, tt.rate, tt.provider, tt.updatedAt)
+		}
+		if got := strings.Join(rate.Path, "->"); got != pathCodes(tt.base, path) {
+			t.Errorf("%s: rate path %s, want %s", tt.name, got, pathCodes(tt.base, path))
+		}
+	}
+
+	zero := []graphEdge{{to: "EUR", rate: storedRate("a", "EUR", "USD", "0", 100), inverse: true}}
+	if _, err := rateAlongPath("USD", "EUR", zero); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("rateAlongPath through a zero rate = %v, want rate unavailable", err)
+	}
+}
+
+func TestIdentityPairsAreFresh(t *testing.T) {
+	rs := cachedService(t)
+	rs.Staleness = currency.StalenessPolicy{DefaultMaxAge: time.Minute}
+	ctx := context.Background()
+
+	for _, asOf := range []int64{0, 1000} {
+		rate, err := rs.GetRate(ctx, "usd", "USD", RateOptions{AsOf: asOf, MaxAge: time.Second, Precision: currency.Precision{Raw: true}})
+		if err != nil {
+			t.Errorf("GetRate(USD, USD) as of %d returned error: %v", asOf, err)
+			continue
+		}
+		if !rate.Rate.Equal(decimal.NewFromInt(1)) || rate.Provider != IdentityProviderName || rate.Age != 0 || rate.Stale {
+			t.Errorf("GetRate(USD, USD) as of %d = %s from %s, age %d, stale %t, want a fresh 1 from %s", asOf, rate.Rate, rate.Provider, rate.Age, rate.Stale, IdentityProviderName)
+		}
+	}
+
+	results := rs.GetRates(ctx, []models.RatePair{{Base: "EUR", Target: "EUR"}}, currency.Precision{Raw: true}, "")
+	if result := results[0]; result.Data == nil || !result.Data.Rate.Equal(decimal.NewFromInt(1)) || result.Data.Stale {
+		t.Errorf("GetRates EUR_EUR = %+v, want a fresh 1", result)
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/db"
+	"assignment1/models"
+	"assignment1/provider"
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+	"time"
+)
+
+// getRateAsOf returns the rate that was effective at asOf (epoch seconds). It
+// resolves over the latest observation of every pair at or before asOf, the
+// same way current lookups resolve over the rates table, so pinned overrides
+// and paths through other currencies are answered as they were served.
+func (rs *RateService) getRateAsOf(ctx context.Context, base string, target string, asOf int64) (models.Rate, error) {
+	log.Printf("API Call: Getting rate for %s to %s as of %s", base, target, time.Unix(asOf, 0).UTC().Format(time.RFC3339))
+	if base == target {
+		return identityRate(base, asOf), nil
+	}
+
+	graph, err := rs.historyGraph(ctx, asOf)
+	if err != nil {
+		return models.Rate{}, err
+	}
+	for _, code := range []string{base, target} {
+		if len(graph.edges[code]) == 0 {
+			log.Printf("History MISS: No pair involving %s recorded at or before %d", code, asOf)
+			return models.Rate{}, rateUnavailable(base, target, "no rate recorded for %s at or before %d", code, asOf)
+		}
+	}
+
+	path, ok := graph.shortestPath(base, target)
+	if !ok {
+		log.Printf("History MISS: No path from %s to %s within %d hops as of %d", base, target, maxPathHops, asOf)
+		return models.Rate{}, rateUnavailable(base, target, "no rate path found from %s to %s at or before %d", base, target, asOf)
+	}
+	rate, err := rateAlongPath(base, target, path)
+	if err != nil {
+		log.Printf("History Error: %v", err)
+		return models.Rate{}, err
+	}
+
+	log.Printf("History HIT: Found rate %s_%s = %s as of %d via %s", base, target, rate.Rate, asOf, strings.Join(rate.Path, "->"))
+	return rate, nil
+}
+
+// historyGraph builds the currency graph from the latest observation of
+// every pair at or before asOf. Overrides are recorded in the history while
+// they are active, so they take part like any other pair.
+func (rs *RateService) historyGraph(ctx context.Context, asOf int64) (*rateGraph, error) {
+	latest := db.DB.Model(&models.RateHistory{}).
+		Select("base, target, MAX(observed_at) AS observed_at").
+		Where("observed_at <= ?", asOf).
+		Group("base, target")
+
+	var rows []models.RateHistory
+	result := db.DB.WithContext(ctx).
+		Joins("JOIN (?) AS latest ON latest.base = rate_histories.base AND latest.target = rate_histories.target AND latest.observed_at = rate_histories.observed_at", latest).
+		Order("rate_histories.id DESC").
+		Find(&rows)
+	if result.Error != nil {
+		log.Printf("History Error: Failed to load rates as of %d, error: %v", asOf, result.Error)
+		return nil, upstream(result.Error, "failed to load rate history as of %d", asOf)
+	}
+
+	rates := make([]models.Rate, 0, len(rows))
+	for _, row := range rows {
+		rates = append(rates, row.ToRate())
+	}
+	return newRateGraph(rates), nil
+}
+
+func (rs *RateService) getRateFromHistory(ctx context.Context, base, target string, asOf int64) (models.Rate, error) {
+	if base == rs.GlobalBaseCurrency {
+		return rs.findHistoricalRate(ctx, base, target, asOf)
+	}
+
+	log.Printf("History Query: Calculating cross rate for %s_%s as of %d using %s as intermediary", base, target, asOf, rs.GlobalBaseCurrency)
+	usdToBase, err := rs.findHistoricalRate(ctx, rs.GlobalBaseCurrency, base, asOf)
+	if err != nil {
+		return models.Rate{}, err
+	}
+
+	usdToTarget, err := rs.findHistoricalRate(ctx, rs.GlobalBaseCurrency, target, asOf)
+	if err != nil {
+		return models.Rate{}, err
+	}
+
+	return rs.calculateCrossRateFromRates(usdToBase, usdToTarget, base, target)
+}
+
+func (rs *RateService) findHistoricalRate(ctx context.Context, base, target string, asOf int64) (models.Rate, error) {
+	var history models.RateHistory
+	result := db.DB.WithContext(ctx).
+		Where("base = ? AND target = ? AND observed_at <= ?", base, target, asOf).
+		Order("observed_at DESC, id DESC").
+		First(&history)
+	if result.Error != nil {
+		log.Printf("History Error: No observation for %s_%s at or before %d, error: %v", base, target, asOf, result.Error)
+		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return models.Rate{}, upstream(result.Error, "failed to load rate history for %s_%s", base, target)
+		}
+		return models.Rate{}, rateUnavailable(base, target, "no rate recorded for %s_%s at or before %d", base, target, asOf)
+	}
+
+	return history.ToRate(), nil
+}
+
+const maxHistoryBuckets = 5000
+
+var historyIntervals = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+// ParseHistoryInterval maps an interval name ("hour", "day") to its bucket
+// size. An empty name defaults to "day".
+func ParseHistoryInterval(name string) (time.Duration, error) {
+	if name == "" {
+		name = "day"
+	}
+	interval, ok := historyIntervals[name]
+	if !ok {
+		return 0, InvalidInput("interval", "unsupported interval %q, expected hour or day", name)
+	}
+	return interval, nil
+}
+
+// GetRateHistory returns the rate for base/target bucketed by interval over
+// [from, to]. Each bucket carries the rate as it stood at the bucket's close;
+// buckets before the first recorded observation are omitted.
+func (rs *RateService) GetRateHistory(ctx context.Context, base, target string, from, to int64, interval time.Duration) ([]models.RatePoint, error) {
+	base, target = normalizeCode(base), normalizeCode(target)
+	log.Printf("API Call: Getting rate history for %s to %s from %d to %d every %s", base, target, from, to, interval)
+
+	if err := rs.validateCurrencies(base, target); err != nil {
+		return nil, err
+	}
+
+	step := int64(interval.Seconds())
+	if step <= 0 {
+		return nil, InvalidInput("interval", "interval must be positive")
+	}
+	if from > to {
+		return nil, InvalidInput("from", "from must not be after to")
+	}
+	start := from - from%step
+	if (to-start)/step+1 > maxHistoryBuckets {
+		return nil, InvalidInput("from", "range too large, at most %d buckets allowed", maxHistoryBuckets)
+	}
+
+	legTargets := []string{target}
+	if base != rs.GlobalBaseCurrency {
+		legTargets = []string{base, target}
+	}
+
+	legs := make([][]models.Rate, len(legTargets))
+	for i, code := range legTargets {
+		series, err := rs.loadLegSeries(ctx, rs.GlobalBaseCurrency, code, start, to)
+		if err != nil {
+			return nil, err
+		}
+		legs[i] = series
+	}
+
+	points := make([]models.RatePoint, 0)
+	cursors := make([]int, len(legs))
+	for bucket := start; bucket <= to; bucket += step {
+		closeAt := bucket + step - 1
+		if closeAt > to {
+			closeAt = to
+		}
+
+		current := make([]models.Rate, len(legs))
+		complete := true
+		for i, series := range legs {
+			for cursors[i] < len(series) && series[cursors[i]].UpdatedAt <= closeAt {
+				cursors[i]++
+			}
+			if cursors[i] == 0 {
+				complete = false
+				continue
+			}
+			current[i] = series[cursors[i]-1]
+		}
+		if !complete {
+			continue
+		}
+
+		rate := current[0]
+		if len(current) == 2 {
+			var err error
+			rate, err = rs.calculateCrossRateFromRates(current[0], current[1], base, target)
+			if err != nil {
+				return nil, err
+			}
+		}
+		points = append(points, models.RatePoint{
+			Base:      base,
+			Target:    target,
+			Time:      bucket,
+			Rate:      rs.Precision.RoundRate(target, rate.Rate, currency.Precision{}),
+			UpdatedAt: rate.UpdatedAt,
+		})
+	}
+
+	log.Printf("History Success: Built %d points for %s_%s", len(points), base, target)
+	return points, nil
+}
+
+// loadLegSeries returns the observations for base/target within [from, to] in
+// chronological order, preceded by the last observation before from (if any)
+// so the first bucket has a value to carry forward.
+func (rs *RateService) loadLegSeries(ctx context.Context, base, target string, from, to int64) ([]models.Rate, error) {
+	var rows []models.RateHistory
+	result := db.DB.WithContext(ctx).
+		Where("base = ? AND target = ? AND observed_at >= ? AND observed_at <= ?", base, target, from, to).
+		Order("observed_at ASC, id ASC").
+		Find(&rows)
+	if result.Error != nil {
+		log.Printf("History Error: Failed to load %s_%s series, error: %v", base, target, result.Error)
+		return nil, upstream(result.Error, "failed to load rate history for %s_%s", base, target)
+	}
+
+	series := make([]models.Rate, 0, len(rows)+1)
+	if previous, err := rs.findHistoricalRate(ctx, base, target, from-1); err == nil {
+		series = append(series, previous)
+	}
+	for _, row := range rows {
+		series = append(series, row.ToRate())
+	}
+
+	if len(series) == 0 {
+		return nil, rateUnavailable(base, target, "no rate history recorded for %s_%s", base, target)
+	}
+	return series, nil
+}
+
+// BackfillHistory loads past observations from p into the rate history.
+// Observations already recorded are left untouched.
+func (rs *RateService) BackfillHistory(ctx context.Context, p provider.HistoricalRateProvider) {
+	log.Printf("History Backfill: Fetching historical rates from %s", p.Name())
+	rates, err := p.GetHistoricalRates(ctx)
+	if err != nil {
+		log.Printf("History Backfill Error: Failed to fetch historical rates from %s, error: %v", p.Name(), err)
+		return
+	}
+
+	history := make([]models.RateHistory, 0, len(rates))
+	for _, rate := range rates {
+		history = append(history, models.NewRateHistory(rate))
+	}
+	rs.syncToHistory(ctx, history)
+	log.Printf("History Backfill: Completed backfill of %d observations from %s", len(history), p.Name())
+}
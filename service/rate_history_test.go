@@ -0,0 +1,72 @@
+package service
+
+import (
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"errors"
+	"github.com/shopspring/decimal"
+	"testing"
+)
+
+func recordHistory(t *testing.T, observedAt int64, provider string, pairs ...string) {
+	t.Helper()
+	for i := 0; i+2 < len(pairs); i += 3 {
+		row := models.RateHistory{
+			Base:       pairs[i],
+			Target:     pairs[i+1],
+			Provider:   provider,
+			ObservedAt: observedAt,
+			Rate:       decimal.RequireFromString(pairs[i+2]),
+		}
+		if err := db.DB.Create(&row).Error; err != nil {
+			t.Fatalf("recording history: %v", err)
+		}
+	}
+}
+
+func TestGetRateAsOf(t *testing.T) {
+	useTestDB(t)
+	recordHistory(t, 1000, "openexchange", "USD", "EUR", "0.8", "USD", "ARS", "1000")
+	recordHistory(t, 1500, OverrideProviderName, "EUR", "ARS", "1300")
+	recordHistory(t, 1000, "file", "GBP", "JPY", "190", "CHF", "GBP", "0.9")
+	rs := alertService(nil)
+
+	tests := []struct {
+		name     string
+		base     string
+		target   string
+		asOf     int64
+		rate     string
+		provider string
+	}{
+		{"triangulated before the override", "EUR", "ARS", 1200, "1250", "openexchange"},
+		{"pinned by an override", "EUR", "ARS", 2000, "1300", OverrideProviderName},
+		{"inverse of the override", "ARS", "EUR", 2000, "0.00076923076923076923", OverrideProviderName},
+		{"path outside the global base", "CHF", "JPY", 2000, "171", "file"},
+	}
+	for _, tt := range tests {
+		rate, err := rs.getRateAsOf(context.Background(), tt.base, tt.target, tt.asOf)
+		if err != nil {
+			t.Errorf("%s: getRateAsOf returned error: %v", tt.name, err)
+			continue
+		}
+		if !rate.Rate.Equal(decimal.RequireFromString(tt.rate)) || rate.Provider != tt.provider {
+			t.Errorf("%s: %s_%s = %s from %s, want %s from %s", tt.name, tt.base, tt.target, rate.Rate, rate.Provider, tt.rate, tt.provider)
+		}
+	}
+
+	if _, err := rs.getRateAsOf(context.Background(), "EUR", "ARS", 500); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("getRateAsOf before any observation = %v, want rate unavailable", err)
+	}
+	if _, err := rs.getRateAsOf(context.Background(), "EUR", "JPY", 2000); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("getRateAsOf across disconnected pairs = %v, want rate unavailable", err)
+	}
+}
+
+func TestGetRateRejectsNegativeAsOf(t *testing.T) {
+	rs := alertService(nil)
+	if _, err := rs.GetRate(context.Background(), "USD", "EUR", RateOptions{AsOf: -1}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("GetRate with a negative as_of = %v, want invalid input", err)
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"assignment1/models"
+	"github.com/shopspring/decimal"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MaxSubscriptionPairs = 200
+
+	defaultSubscriberBuffer = 64
+	defaultRetainedUpdates  = 1024
+	defaultResumeWindow     = 5 * time.Minute
+)
+
+// RateUpdate is a change to the rate of one pair. IDs increase with every
+// update the hub publishes and keep increasing across restarts.
+type RateUpdate struct {
+	ID   uint64
+	Rate models.Rate
+	// Previous is the rate last published for the pair, zero for the first.
+	Previous decimal.Decimal
+}
+
+// RateHub fans rate changes out to subscribers. It remembers the last rate
+// published for every subscribed pair so that only actual changes are sent.
+// A subscriber that falls Buffer updates behind is dropped and its channel
+// closed rather than holding up the sync.
+//
+// The last Retain updates are kept so a subscriber that reconnects can resume
+// after the last update it saw. Pairs keep being tracked for ResumeWindow
+// after their last subscriber leaves so the updates in between are recorded.
+type RateHub struct {
+	Buffer       int
+	Retain       int
+	ResumeWindow time.Duration
+
+	mutex       sync.Mutex
+	nextID      uint64
+	recent      []RateUpdate
+	evicted     uint64
+	subscribers map[*Subscription]struct{}
+	pairs       map[string]*trackedPair
+}
+
+type trackedPair struct {
+	last      models.Rate
+	published bool
+	// since is the last update ID published before tracking began.
+	since       uint64
+	subscribers int
+	idleSince   time.Time
+}
+
+// Subscription receives the updates for a set of pairs until it is closed.
+// When resuming succeeded, Backlog holds the updates published after the
+// requested ID; otherwise the caller should send a snapshot as of StartID.
+type Subscription struct {
+	StartID uint64
+	Resumed bool
+	Backlog []RateUpdate
+
+	hub     *RateHub
+	pairs   map[string]bool
+	updates chan RateUpdate
+	closed  bool
+}
+
+func NewRateHub(buffer int) *RateHub {
+	if buffer <= 0 {
+		buffer = defaultSubscriberBuffer
+	}
+	return &RateHub{
+		Buffer:       buffer,
+		Retain:       defaultRetainedUpdates,
+		ResumeWindow: defaultResumeWindow,
+		// Seeding from the clock keeps IDs from a previous run below the
+		// current ones, so they are not mistaken for resumable IDs.
+		nextID:      uint64(time.Now().UnixMicro()),
+		subscribers: make(map[*Subscription]struct{}),
+		pairs:       make(map[string]*trackedPair),
+	}
+}
+
+// Subscribe registers for pairs. A non-zero lastID resumes after that update
+// when the hub still holds every update since then for all of the pairs.
+func (h *RateHub) Subscribe(pairs []models.RatePair, lastID uint64) *Subscription {
+	sub := &Subscription{
+		hub:     h,
+		pairs:   make(map[string]bool, len(pairs)),
+		updates: make(chan RateUpdate, h.Buffer),
+	}
+	for _, pair := range pairs {
+		sub.pairs[pair.Base+"_"+pair.Target] = true
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	sub.StartID = h.nextID
+	if lastID > 0 && h.canResume(sub.pairs, lastID) {
+		sub.Resumed = true
+		for _, update := range h.recent {
+			if update.ID > lastID && sub.pairs[update.Rate.Base+"_"+update.Rate.Target] {
+				sub.Backlog = append(sub.Backlog, update)
+			}
+		}
+	}
+	h.track(sub.pairs)
+	h.subscribers[sub] = struct{}{}
+	log.Printf("Updates: Subscriber added for %d pairs (resumed=%t), %d subscribers", len(pairs), sub.Resumed, len(h.subscribers))
+	return sub
+}
+
+func (h *RateHub) canResume(keys map[string]bool, lastID uint64) bool {
+	if lastID > h.nextID || lastID < h.evicted {
+		return false
+	}
+	for key := range keys {
+		tracked, ok := h.pairs[key]
+		if !ok || tracked.since > lastID {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *RateHub) track(keys map[string]bool) {
+	for key := range keys {
+		tracked, ok := h.pairs[key]
+		if !ok {
+			tracked = &trackedPair{since: h.nextID}
+			h.pairs[key] = tracked
+		}
+		tracked.subscribers++
+	}
+}
+
+func (h *RateHub) untrack(keys map[string]bool) {
+	now := time.Now()
+	for key := range keys {
+		if tracked, ok := h.pairs[key]; ok {
+			tracked.subscribers--
+			if tracked.subscribers == 0 {
+				tracked.idleSince = now
+			}
+		}
+	}
+}
+
+// Add extends the subscription with pairs and returns the ID a snapshot of
+// them is current as of.
+func (s *Subscription) Add(pairs []models.RatePair) uint64 {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	added := make(map[string]bool)
+	for _, pair := range pairs {
+		key := pair.Base + "_" + pair.Target
+		if !s.pairs[key] {
+			s.pairs[key] = true
+			added[key] = true
+		}
+	}
+	if !s.closed {
+		s.hub.track(added)
+	}
+	return s.hub.nextID
+}
+
+// Remove stops the subscription from receiving updates for pairs.
+func (s *Subscription) Remove(pairs []models.RatePair) {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	removed := make(map[string]bool)
+	for _, pair := range pairs {
+		key := pair.Base + "_" + pair.Target
+		if s.pairs[key] {
+			delete(s.pairs, key)
+			removed[key] = true
+		}
+	}
+	if !s.closed {
+		s.hub.untrack(removed)
+	}
+}
+
+// Size is the number of pairs subscribed to.
+func (s *Subscription) Size() int {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	return len(s.pairs)
+}
+
+// Updates is closed when the subscription is closed or dropped.
+func (s *Subscription) Updates() <-chan RateUpdate {
+	return s.updates
+}
+
+func (s *Subscription) Close() {
+	s.hub.mutex.Lock()
+	defer s.hub.mutex.Unlock()
+	s.hub.remove(s)
+}
+
+// remove must be called with the hub mutex held.
+func (h *RateHub) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	close(sub.updates)
+	delete(h.subscribers, sub)
+	h.untrack(sub.pairs)
+}
+
+// Pairs returns every tracked pair, forgetting pairs that have had no
+// subscriber for longer than ResumeWindow.
+func (h *RateHub) Pairs() []models.RatePair {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	pairs := make([]models.RatePair, 0, len(h.pairs))
+	for key, tracked := range h.pairs {
+		if tracked.subscribers == 0 && time.Since(tracked.idleSince) > h.ResumeWindow {
+			delete(h.pairs, key)
+			continue
+		}
+		base, target, _ := strings.Cut(key, "_")
+		pairs = append(pairs, models.RatePair{Base: base, Target: target})
+	}
+	return pairs
+}
+
+// Seed records rates sent to a subscriber as a snapshot as the last
+// published rate of pairs nothing has been published for yet, so the next
+// Publish only sends them if they changed.
+func (h *RateHub) Seed(rates []models.Rate) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, rate := range rates {
+		if tracked, ok := h.pairs[rate.Base+"_"+rate.Target]; ok && !tracked.published {
+			tracked.last, tracked.published = rate, true
+		}
+	}
+}
+
+// Publish sends the rates that differ from the last published rate of their
+// pair to the pair's subscribers and returns how many changed.
+func (h *RateHub) Publish(rates []models.Rate) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	changed := 0
+	for _, rate := range rates {
+		key := rate.Base + "_" + rate.Target
+		tracked, ok := h.pairs[key]
+		if !ok {
+			continue
+		}
+		previous, published := tracked.last, tracked.published
+		tracked.last, tracked.published = rate, true
+		if published && previous.Rate.Equal(rate.Rate) {
+			continue
+		}
+
+		h.nextID++
+		changed++
+		update := RateUpdate{ID: h.nextID, Rate: rate, Previous: previous.Rate}
+		h.recent = append(h.recent, update)
+		if len(h.recent) > h.Retain {
+			h.evicted = h.recent[0].ID
+			h.recent = h.recent[1:]
+		}
+
+		for sub := range h.subscribers {
+			if !sub.pairs[key] {
+				continue
+			}
+			select {
+			case sub.updates <- update:
+			default:
+				log.Printf("Updates: Dropping subscriber that fell %d updates behind", h.Buffer)
+				h.remove(sub)
+			}
+		}
+	}
+	return changed
+}
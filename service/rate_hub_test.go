@@ -0,0 +1,150 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/models"
+	"context"
+	"slices"
+	"testing"
+)
+
+var usdEUR = []models.RatePair{{Base: "USD", Target: "EUR"}}
+
+// publishEUR publishes each rate for USD_EUR and returns the update IDs.
+func publishEUR(t *testing.T, h *RateHub, rates ...string) []uint64 {
+	t.Helper()
+	var ids []uint64
+	for _, rate := range rates {
+		if h.Publish([]models.Rate{usdRate("EUR", rate)}) != 1 {
+			t.Fatalf("publishing USD_EUR %s changed nothing", rate)
+		}
+		ids = append(ids, h.nextID)
+	}
+	return ids
+}
+
+func updateIDs(updates []RateUpdate) []uint64 {
+	ids := make([]uint64, 0, len(updates))
+	for _, update := range updates {
+		ids = append(ids, update.ID)
+	}
+	return ids
+}
+
+func TestSnapshotSeedsHub(t *testing.T) {
+	rs := cachedService(t)
+	rs.Updates = NewRateHub(0)
+	sub, err := rs.Subscribe(usdEUR, 0)
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer sub.Close()
+
+	snapshot := rs.InitialUpdates(context.Background(), sub, usdEUR, RateOptions{Precision: currency.Precision{Raw: true}})
+	if len(snapshot) != 1 || !snapshot[0].Snapshot || snapshot[0].ID != sub.StartID {
+		t.Fatalf("initial updates = %+v, want one snapshot as of %d", snapshot, sub.StartID)
+	}
+
+	if changed := rs.Updates.Publish([]models.Rate{usdRate("EUR", "0.8")}); changed != 0 {
+		t.Errorf("publishing the snapshot rate changed %d pairs, want 0", changed)
+	}
+	if changed := rs.Updates.Publish([]models.Rate{usdRate("EUR", "0.81")}); changed != 1 {
+		t.Fatalf("publishing a new rate changed %d pairs, want 1", changed)
+	}
+	update := <-sub.Updates()
+	if !update.Rate.Rate.Equal(usdRate("EUR", "0.81").Rate) || !update.Previous.Equal(usdRate("EUR", "0.8").Rate) {
+		t.Errorf("update = %s from %s, want 0.81 from the snapshot's 0.8", update.Rate.Rate, update.Previous)
+	}
+	select {
+	case extra := <-sub.Updates():
+		t.Errorf("unexpected update %+v", extra)
+	default:
+	}
+}
+
+func TestHubResumesFromKnownID(t *testing.T) {
+	h := NewRateHub(0)
+	first := h.Subscribe(usdEUR, 0)
+	ids := publishEUR(t, h, "0.8", "0.9", "1.0")
+	first.Close()
+
+	sub := h.Subscribe(usdEUR, ids[0])
+	defer sub.Close()
+	if !sub.Resumed || !slices.Equal(updateIDs(sub.Backlog), ids[1:]) {
+		t.Errorf("resume after %d = resumed %t with %v, want the backlog %v", ids[0], sub.Resumed, updateIDs(sub.Backlog), ids[1:])
+	}
+
+	latest := h.Subscribe(usdEUR, ids[2])
+	defer latest.Close()
+	if !latest.Resumed || len(latest.Backlog) != 0 {
+		t.Errorf("resume after the latest update = resumed %t with %v, want an empty backlog", latest.Resumed, updateIDs(latest.Backlog))
+	}
+
+	// GBP was not tracked when ids[0] was published, so its updates since
+	// then are unknown.
+	wider := h.Subscribe(append(usdEUR, models.RatePair{Base: "USD", Target: "GBP"}), ids[0])
+	defer wider.Close()
+	if wider.Resumed {
+		t.Error("resumed with a pair tracked only after the requested ID")
+	}
+
+	future := h.Subscribe(usdEUR, ids[2]+100)
+	defer future.Close()
+	if future.Resumed {
+		t.Error("resumed from an ID the hub has not published")
+	}
+}
+
+func TestHubCannotResumeFromEvictedID(t *testing.T) {
+	h := NewRateHub(0)
+	h.Retain = 2
+	first := h.Subscribe(usdEUR, 0)
+	defer first.Close()
+	ids := publishEUR(t, h, "0.8", "0.9", "1.0", "1.1")
+
+	if len(h.recent) != 2 || h.evicted != ids[1] {
+		t.Fatalf("hub retains %v with %d evicted, want the last 2 with %d evicted", updateIDs(h.recent), h.evicted, ids[1])
+	}
+
+	sub := h.Subscribe(usdEUR, ids[0])
+	defer sub.Close()
+	if sub.Resumed || len(sub.Backlog) != 0 || sub.StartID != ids[3] {
+		t.Errorf("resume from an evicted ID = resumed %t, start %d, want a snapshot as of %d", sub.Resumed, sub.StartID, ids[3])
+	}
+
+	oldest := h.Subscribe(usdEUR, ids[1])
+	defer oldest.Close()
+	if !oldest.Resumed || !slices.Equal(updateIDs(oldest.Backlog), ids[2:]) {
+		t.Errorf("resume from the last evicted ID = resumed %t with %v, want %v", oldest.Resumed, updateIDs(oldest.Backlog), ids[2:])
+	}
+}
+
+func TestHubDropsOverflowingSubscriber(t *testing.T) {
+	h := NewRateHub(2)
+	slow := h.Subscribe(usdEUR, 0)
+	other := h.Subscribe([]models.RatePair{{Base: "USD", Target: "GBP"}}, 0)
+	defer other.Close()
+
+	ids := publishEUR(t, h, "0.8", "0.9", "1.0")
+	var received []uint64
+	for update := range slow.Updates() {
+		received = append(received, update.ID)
+	}
+	if !slices.Equal(received, ids[:2]) {
+		t.Errorf("dropped subscriber received %v before its channel closed, want %v", received, ids[:2])
+	}
+	if _, ok := h.subscribers[slow]; ok || len(h.subscribers) != 1 {
+		t.Errorf("hub still has %d subscribers, want only the other one", len(h.subscribers))
+	}
+	if len(h.recent) != 3 || h.evicted != 0 {
+		t.Errorf("hub retains %v with %d evicted, want every update", updateIDs(h.recent), h.evicted)
+	}
+
+	// The dropped subscriber can resume from the last update it received.
+	resumed := h.Subscribe(usdEUR, received[len(received)-1])
+	defer resumed.Close()
+	if !resumed.Resumed || !slices.Equal(updateIDs(resumed.Backlog), ids[2:]) {
+		t.Errorf("resume after dropping = resumed %t with %v, want %v", resumed.Resumed, updateIDs(resumed.Backlog), ids[2:])
+	}
+	slow.Close()
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"log"
+)
+
+// GetRateMatrix returns the rate from base to every supported currency. All
+// GlobalBaseCurrency rows are loaded with one query and rebased in a single
+// pass instead of resolving each target as its own cross rate. Active
+// overrides win over stored rows, both for legs and for base's own pairs.
+func (rs *RateService) GetRateMatrix(ctx context.Context, base string, precision currency.Precision) (models.RateMatrixDto, error) {
+	base = normalizeCode(base)
+	log.Printf("API Call: Getting rate matrix for %s", base)
+
+	if err := rs.validateCurrencies(base); err != nil {
+		return models.RateMatrixDto{}, err
+	}
+
+	var rows []models.Rate
+	result := db.DB.WithContext(ctx).Where("base = ?", rs.GlobalBaseCurrency).Find(&rows)
+	if result.Error != nil {
+		log.Printf("DB Error: Failed to load %s rates, error: %v", rs.GlobalBaseCurrency, result.Error)
+		return models.RateMatrixDto{}, upstream(result.Error, "failed to load rates for %s", base)
+	}
+	log.Printf("DB Success: Loaded %d %s rates", len(rows), rs.GlobalBaseCurrency)
+	for i, row := range rows {
+		if pinned, ok := rs.activeOverride(row.Base, row.Target); ok {
+			rows[i] = pinned
+		}
+	}
+
+	var usdToBase models.Rate
+	found := base == rs.GlobalBaseCurrency
+	for _, row := range rows {
+		if row.Target == base {
+			usdToBase = row
+			found = true
+			break
+		}
+	}
+	if !found {
+		return models.RateMatrixDto{}, currencyNotSupported(base)
+	}
+
+	matrix := models.RateMatrixDto{
+		Base:  base,
+		Rates: make(map[string]decimal.Decimal, len(rows)),
+	}
+	for _, row := range rows {
+		rate := row
+		if pinned, ok := rs.activeOverride(base, row.Target); ok {
+			rate = pinned
+		} else if base != rs.GlobalBaseCurrency {
+			var err error
+			rate, err = rs.calculateCrossRateFromRates(usdToBase, row, base, row.Target)
+			if err != nil {
+				return models.RateMatrixDto{}, err
+			}
+		}
+		matrix.Rates[row.Target] = rs.Precision.RoundRate(row.Target, rate.Rate, precision)
+		if matrix.UpdatedAt == 0 || rate.UpdatedAt < matrix.UpdatedAt {
+			matrix.UpdatedAt = rate.UpdatedAt
+		}
+	}
+
+	log.Printf("Matrix Success: Built %d rates for %s", len(matrix.Rates), base)
+	return matrix, nil
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"assignment1/cache"
+	"assignment1/currency"
 	"assignment1/db"
 	"assignment1/models"
+	"assignment1/pricing"
 	"assignment1/provider"
-	"fmt"
+	"assignment1/utility"
+	"context"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm/clause"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -17,141 +22,223 @@ type RateService struct {
 	Cache               cache.RateCache
 	BackgroundTaskTimer time.Duration
 	GlobalBaseCurrency  string
+	RoundingMode        utility.RoundingMode
+	Precision           currency.PrecisionPolicy
+	Currencies          *currency.Registry
+	// Spreads turns mid rates into bid and ask quotes; nil quotes at mid.
+	Spreads   *pricing.Spreads
+	Staleness currency.StalenessPolicy
+	// Updates receives the rates changed by each sync; nil disables
+	// subscriptions.
+	Updates *RateHub
+	// Webhooks delivers the alerts fired by each sync; nil only logs them.
+	Webhooks *WebhookSender
+
+	overrides overrideSet
+	graph     graphState
+	// lookups collapses concurrent cache misses for the same pair and fetches
+	// concurrent provider calls into one.
+	lookups singleflight.Group
+	fetches singleflight.Group
 }
 
-func (rs *RateService) GetRate(base string, target string) (models.RateDto, error) {
-	log.Printf("API Call: Getting rate for %s to %s", base, target)
+// RateOptions tunes a single rate lookup. The zero value returns the current
+// rate rounded by the service's precision policy.
+type RateOptions struct {
+	// AsOf (epoch seconds) returns the rate effective at that instant.
+	AsOf      int64
+	Precision currency.Precision
+	// Client identifies the API client for client specific spreads.
+	Client string
+	// MaxAge rejects the rate with a StaleRateError when it is older.
+	MaxAge time.Duration
+}
+
+func (rs *RateService) GetRate(ctx context.Context, base string, target string, opts RateOptions) (models.RateDto, error) {
+	base, target = normalizeCode(base), normalizeCode(target)
+	if err := rs.validateCurrencies(base, target); err != nil {
+		return models.RateDto{}, err
+	}
+	if opts.AsOf < 0 {
+		return models.RateDto{}, InvalidInput("as_of", "as_of must be a positive epoch timestamp")
+	}
 
 	var rate models.Rate
 	var err error
+	if opts.AsOf > 0 {
+		rate, err = rs.getRateAsOf(ctx, base, target, opts.AsOf)
+	} else {
+		log.Printf("API Call: Getting rate for %s to %s", base, target)
+		rate, err = rs.getRate(ctx, base, target)
+	}
+	if err != nil {
+		return models.RateDto{}, err
+	}
 
-	// Step 1: Try to get from cache
-	log.Printf("Step 1: Checking cache for %s_%s", base, target)
-	rate, found := rs.getRateFromCache(base, target)
-
-	if found {
-		log.Printf("Cache HIT: Found rate %s_%s = %.6f", base, target, rate.Rate)
-		resp := models.NewRateDto(rate)
-		return resp, nil
+	dto := rs.newRateDto(rate, opts)
+	if age := time.Duration(dto.Age) * time.Second; opts.MaxAge > 0 && age > opts.MaxAge {
+		log.Printf("Staleness: Rejecting %s_%s, age %s exceeds requested max age %s", base, target, age, opts.MaxAge)
+		return models.RateDto{}, &StaleRateError{Base: base, Target: target, Age: age, MaxAge: opts.MaxAge}
 	}
+	return dto, nil
+}
 
-	// Step 2: Cache miss, try database
-	log.Printf("Cache MISS: Checking database for %s_%s", base, target)
-	rate, err = rs.getRateFromDB(base, target)
+// normalizeCode upper-cases a currency code. The catalog matches codes in any
+// case, but cache keys, stored rows, overrides and the graph use upper case.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
 
-	if err == nil {
-		log.Printf("Database HIT: Found rate %s_%s = %.6f", base, target, rate.Rate)
-		// Cache the result for future requests
-		pair := base + "_" + target
-		rs.Cache.Set(pair, rate, rs.Expiry)
-		log.Printf("Cached rate %s_%s for future requests", base, target)
+// normalizePairs returns pairs with normalized codes.
+func normalizePairs(pairs []models.RatePair) []models.RatePair {
+	normalized := make([]models.RatePair, len(pairs))
+	for i, pair := range pairs {
+		normalized[i] = models.RatePair{Base: normalizeCode(pair.Base), Target: normalizeCode(pair.Target)}
+	}
+	return normalized
+}
 
-		resp := models.NewRateDto(rate)
-		return resp, nil
-	} else {
-		log.Printf("Database MISS: Rate not found for %s_%s, error: %v", base, target, err)
-		return models.RateDto{}, err
+// validateCurrencies rejects codes missing from the currency catalog before
+// any cache or database lookup is made.
+func (rs *RateService) validateCurrencies(codes ...string) error {
+	if rs.Currencies == nil {
+		return nil
 	}
+	for _, code := range codes {
+		if !rs.Currencies.IsKnown(code) {
+			log.Printf("Validation Error: Unknown currency code %s", code)
+			return unknownCurrency(code)
+		}
+	}
+	return nil
 }
 
-func (rs *RateService) getRateFromDB(base, target string) (models.Rate, error) {
-	log.Printf("DB Query: Attempting to fetch rate from database for %s_%s", base, target)
+// newRateDto quotes bid and ask around the unrounded mid rate, rounds all
+// three with the precision policy and reports the rate's age, measured at
+// opts.AsOf for historical lookups.
+func (rs *RateService) newRateDto(rate models.Rate, opts RateOptions) models.RateDto {
+	bid, ask := pricing.Quote(rate.Rate, rs.Spreads.Spread(opts.Client, rate.Base, rate.Target))
+	rate.Rate = rs.Precision.RoundRate(rate.Target, rate.Rate, opts.Precision)
+	dto := models.NewRateDto(rate)
+	dto.Bid = rs.Precision.RoundRate(rate.Target, bid, opts.Precision)
+	dto.Ask = rs.Precision.RoundRate(rate.Target, ask, opts.Precision)
+
+	at := opts.AsOf
+	if at == 0 {
+		at = time.Now().Unix()
+	}
+	dto.Age = max(at-rate.UpdatedAt, 0)
+	if maxAge := rs.Staleness.MaxAgeFor(rate.Base, rate.Target); maxAge > 0 {
+		dto.Stale = time.Duration(dto.Age)*time.Second > maxAge
+	}
+	return dto
+}
 
-	if base == rs.GlobalBaseCurrency {
-		// Only look in for direct pair if base is the global currency
-		var rate models.Rate
-		result := db.DB.Where("base = ? AND target = ?", base, target).First(&rate)
-		if result.Error != nil {
-			log.Printf("DB Error: Direct pair not found for %s_%s, error: %v", base, target, result.Error)
-			return models.Rate{}, fmt.Errorf("provided currency %s is currently not supported", target)
-		}
-		log.Printf("DB Success: Found direct pair %s_%s = %.6f", base, target, rate.Rate)
+// getRate resolves the unrounded rate for base/target from an active
+// override, then the cache, falling back to the currency graph built from the
+// database.
+func (rs *RateService) getRate(ctx context.Context, base string, target string) (models.Rate, error) {
+	if base == target {
+		return identityRate(base, time.Now().Unix()), nil
+	}
+	if rate, ok := rs.activeOverride(base, target); ok {
+		log.Printf("Override HIT: %s_%s pinned to %s", base, target, rate.Rate)
 		return rate, nil
 	}
 
-	// Otherwise, calculate cross rate using USD as intermediary
-	log.Printf("DB Query: Calculating cross rate for %s_%s using %s as intermediary", base, target, rs.GlobalBaseCurrency)
-	var usdToBase, usdToTarget models.Rate
+	// Step 1: Try to get from cache
+	log.Printf("Step 1: Checking cache for %s_%s", base, target)
+	rate, found := rs.getRateFromCache(ctx, base, target)
 
-	resBase := db.DB.Where("base = ? AND target = ?", rs.GlobalBaseCurrency, base).First(&usdToBase)
-	if resBase.Error != nil {
-		log.Printf("DB Error: Could not find %s_%s in database, error: %v", rs.GlobalBaseCurrency, base, resBase.Error)
-		return models.Rate{}, fmt.Errorf("provided currency %s is currently not supported", base)
+	if found {
+		log.Printf("Cache HIT: Found rate %s_%s = %s", base, target, rate.Rate)
+		return rate, nil
 	}
 
-	resTarget := db.DB.Where("base = ? AND target = ?", rs.GlobalBaseCurrency, target).First(&usdToTarget)
-	if resTarget.Error != nil {
-		log.Printf("DB Error: Could not find %s_%s in database, error: %v", rs.GlobalBaseCurrency, target, resTarget.Error)
-		return models.Rate{}, fmt.Errorf("provided currency %s is currently not supported", target)
+	// Step 2: Cache miss, resolve over the stored pairs. Concurrent misses
+	// for the same pair share one lookup.
+	pair := base + "_" + target
+	rate, shared, err := coalesce(ctx, &rs.lookups, pair, func(ctx context.Context) (models.Rate, error) {
+		return rs.getRateFromDB(ctx, base, target)
+	})
+	if err != nil {
+		return models.Rate{}, err
 	}
+	if shared {
+		log.Printf("Coalesced: Shared in-flight lookup for %s", pair)
+	}
+	return rate, nil
+}
 
-	log.Printf("DB Success: Found %s_%s = %.6f and %s_%s = %.6f",
-		rs.GlobalBaseCurrency, base, usdToBase.Rate,
-		rs.GlobalBaseCurrency, target, usdToTarget.Rate)
+// getRateFromDB resolves base/target from the stored pairs and caches the
+// result for future requests.
+func (rs *RateService) getRateFromDB(ctx context.Context, base, target string) (models.Rate, error) {
+	log.Printf("Cache MISS: Checking database for %s_%s", base, target)
+	rate, err := rs.getRateFromGraph(ctx, base, target)
+	if err != nil {
+		log.Printf("Database MISS: Rate not found for %s_%s, error: %v", base, target, err)
+		return models.Rate{}, err
+	}
 
-	rate := rs.calculateCrossRateFromRates(usdToBase, usdToTarget, base, target)
-	log.Printf("DB Success: Calculated cross rate %s_%s = %.6f", base, target, rate.Rate)
+	log.Printf("Database HIT: Found rate %s_%s = %s", base, target, rate.Rate)
+	rs.Cache.Set(ctx, base+"_"+target, rate, rs.Expiry)
+	log.Printf("Cached rate %s_%s for future requests", base, target)
 	return rate, nil
 }
 
-func (rs *RateService) getRateFromCache(base, target string) (models.Rate, bool) {
+func (rs *RateService) getRateFromCache(ctx context.Context, base, target string) (models.Rate, bool) {
 	pair := base + "_" + target
 
 	log.Printf("Cache Lookup: Checking for key '%s'", pair)
-	rate, found := rs.Cache.Get(pair, rs.Expiry)
+	rate, found := rs.Cache.Get(ctx, pair, rs.Expiry)
 	if found {
-		log.Printf("Cache HIT: Found %s = %.6f", pair, rate.Rate)
+		log.Printf("Cache HIT: Found %s = %s", pair, rate.Rate)
 		return rate, true
 	}
 
-	log.Printf("Cache MISS: Direct pair %s not found, trying cross-rate calculation", pair)
-	usdToBase, foundBase := rs.Cache.Get(rs.GlobalBaseCurrency+"_"+base, rs.Expiry)
-	usdToTarget, foundTarget := rs.Cache.Get(rs.GlobalBaseCurrency+"_"+target, rs.Expiry)
+	log.Printf("Cache MISS: Pair %s not cached", pair)
+	return models.Rate{}, false
+}
 
-	if foundBase && foundTarget {
-		log.Printf("Cache Cross-Rate: Found %s_%s = %.6f and %s_%s = %.6f",
-			rs.GlobalBaseCurrency, base, usdToBase.Rate,
-			rs.GlobalBaseCurrency, target, usdToTarget.Rate)
+// crossRatePrecision is the number of decimal places kept when dividing two
+// legs. Division is the only inexact step, so it is rounded explicitly.
+const crossRatePrecision = 20
 
-		rate := rs.calculateCrossRateFromRates(usdToBase, usdToTarget, base, target)
-		log.Printf("Cache Cross-Rate: Calculated and cached %s = %.6f", pair, rate.Rate)
-		return rate, true
+func (rs *RateService) calculateCrossRateFromRates(usdToBase, usdToTarget models.Rate, baseCode, targetCode string) (models.Rate, error) {
+	if usdToBase.Rate.IsZero() {
+		return models.Rate{}, rateUnavailable(baseCode, targetCode, "cannot derive %s_%s from a zero %s_%s rate", baseCode, targetCode, usdToBase.Base, usdToBase.Target)
 	}
+	crossRate := usdToTarget.Rate.DivRound(usdToBase.Rate, crossRatePrecision)
+	// A cross rate is only as fresh as its older leg.
+	updatedAt := min(usdToBase.UpdatedAt, usdToTarget.UpdatedAt)
 
-	log.Printf("Cache MISS: Cross-rate calculation failed for %s_%s", base, target)
-	return models.Rate{}, false
-}
-
-func (rs *RateService) calculateCrossRateFromRates(usdToBase, usdToTarget models.Rate, baseCode, targetCode string) models.Rate {
-	crossRate := usdToTarget.Rate / usdToBase.Rate
-	updatedAt := usdToTarget.UpdatedAt
-	if usdToBase.UpdatedAt > updatedAt {
-		updatedAt = usdToBase.UpdatedAt
+	providerName := usdToBase.Provider
+	if usdToTarget.Provider != usdToBase.Provider {
+		providerName = usdToBase.Provider + "+" + usdToTarget.Provider
 	}
 
-	pair := baseCode + "_" + targetCode
-	rate := models.Rate{
+	return models.Rate{
 		Base:      baseCode,
 		Target:    targetCode,
 		Rate:      crossRate,
+		Provider:  providerName,
 		UpdatedAt: updatedAt,
-	}
-
-	rs.Cache.Set(pair, rate, rs.Expiry)
-
-	return rate
+		Path:      []string{baseCode, usdToBase.Base, targetCode},
+	}, nil
 }
 
 // Use this method if you want to get the rates on demand from the API Provider
-func (rs *RateService) getRateFromProvider(base, target string) (models.Rate, error) {
+func (rs *RateService) getRateFromProvider(ctx context.Context, base, target string) (models.Rate, error) {
 	log.Printf("Provider Call: Fetching rates from external API for %s_%s", base, target)
-	rates, err := rs.Provider.GetRates()
+	rates, err := rs.fetchRates(ctx)
 	if err != nil {
 		log.Printf("Provider Error: Failed to fetch rates from API, error: %v", err)
-		return models.Rate{}, err
+		return models.Rate{}, upstream(err, "failed to fetch rates from provider")
 	}
 	log.Printf("Provider Success: Fetched %d rates from external API", len(rates))
 
+	// Persisting the fetched rates outlives the request that triggered it.
+	syncCtx := context.WithoutCancel(ctx)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -159,7 +246,7 @@ func (rs *RateService) getRateFromProvider(base, target string) (models.Rate, er
 			}
 		}()
 		log.Printf("Background Task: Starting database sync for %d rates", len(rates))
-		rs.syncToDB(rates)
+		rs.syncToDB(syncCtx, rates)
 		log.Printf("Background Task: Completed database sync")
 	}()
 	go func() {
@@ -169,21 +256,42 @@ func (rs *RateService) getRateFromProvider(base, target string) (models.Rate, er
 			}
 		}()
 		log.Printf("Background Task: Starting cache sync for %d rates", len(rates))
-		rs.syncToCache(rates)
+		rs.syncToCache(syncCtx, rates)
 		log.Printf("Background Task: Completed cache sync")
 	}()
 
-	return rs.calculateCrossRate(rates, base, target)
+	return rs.calculateCrossRate(ctx, rates, base, target)
 }
 
-func (rs *RateService) calculateCrossRate(rates map[string]models.Rate, base, target string) (models.Rate, error) {
+// fetchRates calls the provider and applies active overrides. Concurrent
+// callers, e.g. an on-demand fetch racing the background sync, share a single
+// provider call, so the returned map must be treated as read-only.
+func (rs *RateService) fetchRates(ctx context.Context) (map[string]models.Rate, error) {
+	rates, shared, err := coalesce(ctx, &rs.fetches, "rates", func(ctx context.Context) (map[string]models.Rate, error) {
+		rates, err := rs.Provider.GetRates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rs.applyOverrides(rates)
+		return rates, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if shared {
+		log.Printf("Coalesced: Shared in-flight provider fetch")
+	}
+	return rates, nil
+}
+
+func (rs *RateService) calculateCrossRate(ctx context.Context, rates map[string]models.Rate, base, target string) (models.Rate, error) {
 	pair := base + "_" + target
 	log.Printf("Cross-Rate Calculation: Attempting to calculate %s from provider data", pair)
 
 	rate, ok := rates[pair]
 
 	if ok {
-		log.Printf("Cross-Rate Success: Direct pair found %s = %.6f", pair, rate.Rate)
+		log.Printf("Cross-Rate Success: Direct pair found %s = %s", pair, rate.Rate)
 		return rate, nil
 	}
 
@@ -195,21 +303,27 @@ func (rs *RateService) calculateCrossRate(rates map[string]models.Rate, base, ta
 
 	if !foundUsdToBase || !foundUsdToTarget {
 		log.Printf("Cross-Rate Error: Missing rates for %s or %s", usdToBaseKey, usdToTargetKey)
-		return models.Rate{}, fmt.Errorf("rate not found for %s_%s", base, target)
+		return models.Rate{}, rateUnavailable(base, target, "rate not found for %s_%s", base, target)
 	}
 
-	log.Printf("Cross-Rate Calculation: Found %s = %.6f and %s = %.6f",
+	log.Printf("Cross-Rate Calculation: Found %s = %s and %s = %s",
 		usdToBaseKey, usdToBase.Rate, usdToTargetKey, usdToTarget.Rate)
 
-	rate = rs.calculateCrossRateFromRates(usdToBase, usdToTarget, base, target)
-	log.Printf("Cross-Rate Success: Calculated %s = %.6f", pair, rate.Rate)
+	rate, err := rs.calculateCrossRateFromRates(usdToBase, usdToTarget, base, target)
+	if err != nil {
+		log.Printf("Cross-Rate Error: %v", err)
+		return models.Rate{}, err
+	}
+	rs.Cache.Set(ctx, pair, rate, rs.Expiry)
+	log.Printf("Cross-Rate Success: Calculated %s = %s", pair, rate.Rate)
 
 	return rate, nil
 }
 
-func (rs *RateService) syncToDBAndCache() {
+func (rs *RateService) syncToDBAndCache(ctx context.Context) {
 	log.Printf("Sync Task: Starting sync to DB and cache")
-	rates, err := rs.Provider.GetRates()
+	rs.LoadOverrides(ctx)
+	rates, err := rs.fetchRates(ctx)
 
 	if err != nil {
 		log.Printf("Sync Error: Failed to fetch rates from provider, error: %v", err)
@@ -217,30 +331,57 @@ func (rs *RateService) syncToDBAndCache() {
 	}
 
 	log.Printf("Sync Task: Successfully fetched %d rates, syncing to cache and DB", len(rates))
-	rs.syncToCache(rates)
-	rs.syncToDB(rates)
+	rs.syncToCache(ctx, rates)
+	rs.syncToDB(ctx, rates)
+	rs.publishChanges(ctx, rates)
+	rs.evaluateAlerts(ctx, rates)
 	log.Printf("Sync Task: Completed sync to DB and cache")
 }
 
-func (rs *RateService) syncToCache(rates map[string]models.Rate) {
+func (rs *RateService) syncToCache(ctx context.Context, rates map[string]models.Rate) {
 	log.Printf("Cache Sync: Syncing %d rates to cache", len(rates))
 	for key, rate := range rates {
-		rs.Cache.Set(key, rate, rs.Expiry)
+		rs.Cache.Set(ctx, key, rate, rs.Expiry)
 	}
 	log.Printf("Cache Sync: Successfully synced %d rates to cache", len(rates))
 }
 
-func (rs *RateService) syncToDB(rates map[string]models.Rate) {
+func (rs *RateService) syncToDB(ctx context.Context, rates map[string]models.Rate) {
 	log.Printf("DB Sync: Syncing %d rates to database", len(rates))
+	history := make([]models.RateHistory, 0, len(rates))
 	for _, rate := range rates {
-		db.DB.Clauses(
+		db.DB.WithContext(ctx).Clauses(
 			clause.OnConflict{
 				Columns:   []clause.Column{{Name: "base"}, {Name: "target"}},
-				DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+				DoUpdates: clause.AssignmentColumns([]string{"rate", "provider", "updated_at"}),
 			},
 		).Create(&rate)
+		history = append(history, models.NewRateHistory(rate))
 	}
 	log.Printf("DB Sync: Successfully synced %d rates to database", len(rates))
+	rs.invalidateGraph()
+
+	rs.syncToHistory(ctx, history)
+}
+
+func (rs *RateService) syncToHistory(ctx context.Context, history []models.RateHistory) {
+	if len(history) == 0 {
+		return
+	}
+	log.Printf("History Sync: Appending %d rates to rate history", len(history))
+	// The same observation can be synced twice (e.g. on-demand fetch and the
+	// background task racing), so duplicates are ignored rather than updated.
+	result := db.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&history, 500)
+	if result.Error != nil {
+		log.Printf("History Sync Error: Failed to append rate history, error: %v", result.Error)
+		return
+	}
+	log.Printf("History Sync: Successfully appended %d rates to rate history", result.RowsAffected)
+}
+
+// SyncNow runs a sync immediately, outside the background schedule.
+func (rs *RateService) SyncNow() {
+	rs.syncToDBAndCache(context.Background())
 }
 
 func (rs *RateService) StartBackgroundSync() {
@@ -249,7 +390,7 @@ func (rs *RateService) StartBackgroundSync() {
 		ticker := time.NewTicker(rs.BackgroundTaskTimer * time.Minute)
 		for range ticker.C {
 			log.Print("Auto sync rates to the db and cache")
-			rs.syncToDBAndCache()
+			rs.syncToDBAndCache(context.Background())
 		}
 	}()
 }
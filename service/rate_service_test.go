@@ -0,0 +1,71 @@
+package service
+
+import (
+	"assignment1/currency"
+	"assignment1/models"
+	"context"
+	"github.com/shopspring/decimal"
+	"testing"
+	"time"
+)
+
+func cachedService(t *testing.T) *RateService {
+	t.Helper()
+	rs := alertService(nil)
+	rs.Currencies = currency.Default()
+	ctx := context.Background()
+	rs.Cache.Set(ctx, "USD_EUR", usdRate("EUR", "0.8"), time.Hour)
+	rs.Cache.Set(ctx, "USD_ARS", usdRate("ARS", "1000"), time.Hour)
+	return rs
+}
+
+func TestLookupsNormalizeCurrencyCodes(t *testing.T) {
+	rs := cachedService(t)
+	ctx := context.Background()
+
+	rate, err := rs.GetRate(ctx, "usd", " Eur", RateOptions{Precision: currency.Precision{Raw: true}})
+	if err != nil {
+		t.Fatalf("GetRate(usd, Eur) returned error: %v", err)
+	}
+	if rate.Base != "USD" || rate.Target != "EUR" || !rate.Rate.Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("GetRate(usd, Eur) = %s_%s %s, want USD_EUR 0.8", rate.Base, rate.Target, rate.Rate)
+	}
+
+	conversion, err := rs.Convert(ctx, "usd", "ars", decimal.NewFromInt(2), "")
+	if err != nil {
+		t.Fatalf("Convert(usd, ars) returned error: %v", err)
+	}
+	if conversion.From != "USD" || conversion.To != "ARS" || conversion.Result != "2000.00" {
+		t.Errorf("Convert(usd, ars) = %s->%s %s, want USD->ARS 2000.00", conversion.From, conversion.To, conversion.Result)
+	}
+
+	results := rs.GetRates(ctx, []models.RatePair{{Base: "usd", Target: "eur"}, {Base: "eur", Target: "ars"}}, currency.Precision{Raw: true}, "")
+	want := []struct{ base, target, rate string }{{"USD", "EUR", "0.8"}, {"EUR", "ARS", "1250"}}
+	for i, result := range results {
+		if result.Error != "" || result.Data == nil {
+			t.Errorf("GetRates %s_%s failed: %s", result.Base, result.Target, result.Error)
+			continue
+		}
+		if result.Base != want[i].base || result.Target != want[i].target || !result.Data.Rate.Equal(decimal.RequireFromString(want[i].rate)) {
+			t.Errorf("GetRates result %d = %s_%s %s, want %s_%s %s", i, result.Base, result.Target, result.Data.Rate, want[i].base, want[i].target, want[i].rate)
+		}
+	}
+}
+
+func TestSubscribeNormalizesCurrencyCodes(t *testing.T) {
+	rs := cachedService(t)
+	rs.Updates = NewRateHub(0)
+	sub, err := rs.Subscribe([]models.RatePair{{Base: "usd", Target: "eur"}}, 0)
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer sub.Close()
+
+	if pairs := rs.Updates.Pairs(); len(pairs) != 1 || pairs[0] != (models.RatePair{Base: "USD", Target: "EUR"}) {
+		t.Fatalf("subscribed pairs = %v, want [USD_EUR]", pairs)
+	}
+	rs.RemovePairs(sub, []models.RatePair{{Base: "Usd", Target: "eur"}})
+	if size := sub.Size(); size != 0 {
+		t.Errorf("subscription still holds %d pairs after removing usd_eur", size)
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"assignment1/models"
+	"assignment1/provider"
+	"time"
+)
+
+// StatusService reports the health of the rate providers.
+type StatusService struct {
+	Mode      string
+	Sync      *provider.MonitoredProvider
+	Providers []*provider.MonitoredProvider
+	// SyncInterval is the background sync period; the sync is reported stale
+	// once it has not succeeded for two intervals.
+	SyncInterval time.Duration
+
+	now func() time.Time
+}
+
+func (ss *StatusService) ProviderStatus() models.ProviderStatusReport {
+	sync := ss.Sync.Status()
+	report := models.ProviderStatusReport{
+		Mode:       ss.Mode,
+		LastSyncAt: sync.LastSuccessAt,
+		Sync:       sync,
+		Providers:  make([]models.ProviderStatus, 0, len(ss.Providers)),
+	}
+	for _, p := range ss.Providers {
+		report.Providers = append(report.Providers, p.Status())
+	}
+
+	now := time.Now
+	if ss.now != nil {
+		now = ss.now
+	}
+	staleAfter := int64((2 * ss.SyncInterval).Seconds())
+	report.Stale = sync.LastSuccessAt == 0 || now().Unix()-sync.LastSuccessAt > staleAfter
+	return report
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"assignment1/models"
+	"assignment1/provider"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recoveringProvider fails until healthy is set.
+type recoveringProvider struct {
+	healthy bool
+}
+
+func (p *recoveringProvider) Name() string {
+	return "recovering"
+}
+
+func (p *recoveringProvider) GetRates(ctx context.Context) (map[string]models.Rate, error) {
+	if !p.healthy {
+		return nil, errors.New("upstream unavailable")
+	}
+	return syncedRates("0.9"), nil
+}
+
+func TestProviderStatusReportsStaleSync(t *testing.T) {
+	upstream := &recoveringProvider{}
+	sync := provider.NewMonitoredProvider(upstream)
+	now := time.Now()
+	ss := &StatusService{
+		Mode:         "failover",
+		Sync:         sync,
+		Providers:    []*provider.MonitoredProvider{sync},
+		SyncInterval: time.Minute,
+		now:          func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	sync.GetRates(ctx)
+	report := ss.ProviderStatus()
+	if !report.Stale || report.LastSyncAt != 0 || report.Sync.ConsecutiveFailures != 1 {
+		t.Errorf("report before any successful sync = %+v, want stale with 1 failure", report)
+	}
+
+	upstream.healthy = true
+	if _, err := sync.GetRates(ctx); err != nil {
+		t.Fatalf("sync after recovery returned error: %v", err)
+	}
+	tests := []struct {
+		elapsed time.Duration
+		stale   bool
+	}{
+		{0, false},
+		{time.Minute, false},
+		{2*time.Minute - 2*time.Second, false},
+		{2*time.Minute + 2*time.Second, true},
+		{time.Hour, true},
+	}
+	for _, tt := range tests {
+		now = time.Now().Add(tt.elapsed)
+		report := ss.ProviderStatus()
+		if report.Stale != tt.stale {
+			t.Errorf("%s after the last sync: stale = %v, want %v", tt.elapsed, report.Stale, tt.stale)
+		}
+		if report.LastSyncAt == 0 || report.Sync.ConsecutiveFailures != 0 || len(report.Providers) != 1 || report.Providers[0].RateCount != 1 {
+			t.Errorf("report after recovery = %+v", report)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"assignment1/models"
+	"context"
+	"log"
+	"time"
+)
+
+// Subscribe registers for changes to pairs, published after every sync. A
+// non-zero lastID resumes after that update where possible.
+func (rs *RateService) Subscribe(pairs []models.RatePair, lastID uint64) (*Subscription, error) {
+	pairs = normalizePairs(pairs)
+	if rs.Updates == nil {
+		return nil, upstream(nil, "rate subscriptions are disabled")
+	}
+	if len(pairs) > MaxSubscriptionPairs {
+		return nil, InvalidInput("pairs", "too many pairs, at most %d allowed", MaxSubscriptionPairs)
+	}
+	if err := rs.validatePairs(pairs); err != nil {
+		return nil, err
+	}
+	return rs.Updates.Subscribe(pairs, lastID), nil
+}
+
+func (rs *RateService) validatePairs(pairs []models.RatePair) error {
+	for _, pair := range pairs {
+		if err := rs.validateCurrencies(pair.Base, pair.Target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddPairs extends sub with pairs, returning the ID a snapshot of them is
+// current as of.
+func (rs *RateService) AddPairs(sub *Subscription, pairs []models.RatePair) (uint64, error) {
+	pairs = normalizePairs(pairs)
+	if sub.Size()+len(pairs) > MaxSubscriptionPairs {
+		return 0, InvalidInput("pairs", "too many pairs, at most %d allowed", MaxSubscriptionPairs)
+	}
+	if err := rs.validatePairs(pairs); err != nil {
+		return 0, err
+	}
+	return sub.Add(pairs), nil
+}
+
+// RemovePairs stops sending sub updates for pairs.
+func (rs *RateService) RemovePairs(sub *Subscription, pairs []models.RatePair) {
+	sub.Remove(normalizePairs(pairs))
+}
+
+// InitialUpdates returns what a new subscriber is sent before live updates:
+// the backlog when it resumed, otherwise a snapshot of pairs.
+func (rs *RateService) InitialUpdates(ctx context.Context, sub *Subscription, pairs []models.RatePair, opts RateOptions) []models.RateUpdateDto {
+	if !sub.Resumed {
+		return rs.Snapshot(ctx, pairs, sub.StartID, opts)
+	}
+	updates := make([]models.RateUpdateDto, 0, len(sub.Backlog))
+	for _, update := range sub.Backlog {
+		updates = append(updates, rs.QuoteUpdate(update, opts))
+	}
+	return updates
+}
+
+// Snapshot returns the current rate of every pair as snapshot updates with
+// the given ID. Pairs without a rate yet are skipped; their first sync
+// publishes one. The rates sent seed the hub, so a sync that leaves them
+// unchanged publishes nothing.
+func (rs *RateService) Snapshot(ctx context.Context, pairs []models.RatePair, id uint64, opts RateOptions) []models.RateUpdateDto {
+	pairs = normalizePairs(pairs)
+	updates := make([]models.RateUpdateDto, 0, len(pairs))
+	rates := make([]models.Rate, 0, len(pairs))
+	for _, pair := range pairs {
+		rate, err := rs.getRate(ctx, pair.Base, pair.Target)
+		if err != nil {
+			log.Printf("Updates: No snapshot for %s_%s, error: %v", pair.Base, pair.Target, err)
+			continue
+		}
+		rates = append(rates, rate)
+		updates = append(updates, models.RateUpdateDto{ID: id, Snapshot: true, Rate: rs.newRateDto(rate, opts)})
+	}
+	if rs.Updates != nil {
+		rs.Updates.Seed(rates)
+	}
+	return updates
+}
+
+// QuoteUpdate prices a published update for a subscriber like GetRate would.
+func (rs *RateService) QuoteUpdate(update RateUpdate, opts RateOptions) models.RateUpdateDto {
+	dto := models.RateUpdateDto{ID: update.ID, Rate: rs.newRateDto(update.Rate, opts)}
+	if !update.Previous.IsZero() {
+		previous := rs.Precision.RoundRate(update.Rate.Target, update.Previous, opts.Precision)
+		dto.PreviousRate = &previous
+	}
+	return dto
+}
+
+// publishChanges resolves every subscribed pair from a freshly synced set of
+// rates and publishes the ones whose rate changed.
+func (rs *RateService) publishChanges(ctx context.Context, rates map[string]models.Rate) {
+	if rs.Updates == nil {
+		return
+	}
+	pairs := rs.Updates.Pairs()
+	if len(pairs) == 0 {
+		return
+	}
+
+	resolved := make([]models.Rate, 0, len(pairs))
+	for _, pair := range pairs {
+		rate, err := rs.resolveSynced(ctx, rates, pair.Base, pair.Target)
+		if err != nil {
+			log.Printf("Updates: Cannot resolve %s_%s for subscribers, error: %v", pair.Base, pair.Target, err)
+			continue
+		}
+		resolved = append(resolved, rate)
+	}
+
+	changed := rs.Updates.Publish(resolved)
+	log.Printf("Updates: Published %d changed rates for %d subscribed pairs", changed, len(pairs))
+}
+
+// resolveSynced resolves a pair from a freshly synced set of rates, falling
+// back to the currency graph for pairs the set cannot derive. Overrides win.
+func (rs *RateService) resolveSynced(ctx context.Context, rates map[string]models.Rate, base, target string) (models.Rate, error) {
+	if base == target {
+		return identityRate(base, time.Now().Unix()), nil
+	}
+	if rate, ok := rs.activeOverride(base, target); ok {
+		return rate, nil
+	}
+	rate, err := rs.calculateCrossRate(ctx, rates, base, target)
+	if err != nil {
+		rate, err = rs.getRateFromGraph(ctx, base, target)
+	}
+	return rate, err
+}
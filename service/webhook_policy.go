@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errCallbackBlocked marks callbacks pointing at internal addresses. Such
+// deliveries are not retried.
+var errCallbackBlocked = errors.New("callback address not allowed")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is not
+// covered by net.IP.IsPrivate.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// CallbackPolicy keeps alert webhooks away from internal hosts. Callbacks
+// resolving to loopback, private, link-local (including 169.254.169.254),
+// unspecified or multicast addresses are rejected unless their host name or
+// address is allow-listed.
+type CallbackPolicy struct {
+	hosts map[string]bool
+	nets  []*net.IPNet
+}
+
+// NewCallbackPolicy builds a policy from allow-list entries, each a host
+// name, an IP address or a CIDR range.
+func NewCallbackPolicy(allowed []string) *CallbackPolicy {
+	p := &CallbackPolicy{hosts: make(map[string]bool)}
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			p.nets = append(p.nets, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			p.nets = append(p.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		p.hosts[entry] = true
+	}
+	return p
+}
+
+// CheckURL validates an alert callback URL: it must be an absolute http or
+// https URL whose host only resolves to allowed addresses.
+func (p *CallbackPolicy) CheckURL(ctx context.Context, raw string) error {
+	callback, err := url.Parse(raw)
+	if err != nil || (callback.Scheme != "http" && callback.Scheme != "https") || callback.Hostname() == "" {
+		return fmt.Errorf("callback_url must be an absolute http or https URL")
+	}
+	host := callback.Hostname()
+	if p.hostAllowed(host) {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("callback_url host %s cannot be resolved", host)
+	}
+	for _, addr := range addrs {
+		if err := p.checkIP(addr.IP); err != nil {
+			return fmt.Errorf("callback_url host %s resolves to %s, which is not allowed", host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// dialContext dials webhook callbacks, checking the address actually
+// connected to so DNS changes after CheckURL cannot reach internal hosts.
+func (p *CallbackPolicy) dialContext(timeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialer := &net.Dialer{Timeout: timeout}
+		if host, _, err := net.SplitHostPort(addr); err != nil || !p.hostAllowed(host) {
+			dialer.Control = func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				return p.checkIP(net.ParseIP(host))
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+func (p *CallbackPolicy) hostAllowed(host string) bool {
+	return p != nil && p.hosts[strings.ToLower(host)]
+}
+
+func (p *CallbackPolicy) checkIP(ip net.IP) error {
+	if ip == nil {
+		return errCallbackBlocked
+	}
+	if p != nil {
+		for _, network := range p.nets {
+			if network.Contains(ip) {
+				return nil
+			}
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", errCallbackBlocked, ip)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestCallbackPolicyCheckIP(t *testing.T) {
+	policy := NewCallbackPolicy([]string{"10.1.0.0/16", "192.168.1.5", "hooks.internal"})
+	tests := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"10.1.2.3", true},
+		{"192.168.1.5", true},
+		{"10.2.0.1", false},
+		{"192.168.1.6", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"172.16.0.1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if err := policy.checkIP(net.ParseIP(tt.ip)); (err == nil) != tt.allowed {
+			t.Errorf("checkIP(%s) = %v, want allowed %v", tt.ip, err, tt.allowed)
+		}
+	}
+}
+
+func TestCallbackPolicyCheckURL(t *testing.T) {
+	strict := NewCallbackPolicy(nil)
+	for _, raw := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://10.0.0.5/hook",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+		"ftp://example.com/hook",
+		"/relative",
+		"http:///hook",
+	} {
+		if err := strict.CheckURL(context.Background(), raw); err == nil {
+			t.Errorf("CheckURL(%s) succeeded, want rejection", raw)
+		}
+	}
+
+	relaxed := NewCallbackPolicy([]string{"127.0.0.1", "Hooks.Internal"})
+	for _, raw := range []string{"http://127.0.0.1:8080/hook", "https://hooks.internal/alerts"} {
+		if err := relaxed.CheckURL(context.Background(), raw); err != nil {
+			t.Errorf("CheckURL(%s) = %v with the host allow-listed", raw, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"assignment1/db"
+	"assignment1/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 5
+	defaultWebhookBackoff     = time.Second
+
+	// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" keyed with the alert's secret, where timestamp is
+	// the WebhookTimestampHeader value.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event-ID"
+)
+
+// WebhookSender delivers alert events. Failed deliveries (network errors,
+// 408, 429 and 5xx responses) are retried up to MaxAttempts times with a
+// doubling backoff; every attempt is recorded as an AlertDelivery. Policy
+// vets callback URLs when alerts are created and again on every connection.
+type WebhookSender struct {
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+	Policy      *CallbackPolicy
+}
+
+func NewWebhookSender(timeout time.Duration, maxAttempts int, backoff time.Duration, policy *CallbackPolicy) *WebhookSender {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+	if backoff <= 0 {
+		backoff = defaultWebhookBackoff
+	}
+	if policy == nil {
+		policy = NewCallbackPolicy(nil)
+	}
+	// No proxy: the policy has to see the callback's own address.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = policy.dialContext(timeout)
+	return &WebhookSender{
+		Client:      &http.Client{Timeout: timeout, Transport: transport},
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Policy:      policy,
+	}
+}
+
+// SignWebhook returns the signature header value for body sent at timestamp.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver sends event to the alert's callback until it is accepted or the
+// attempts run out, and reports whether it was accepted.
+func (w *WebhookSender) Deliver(ctx context.Context, alert models.RateAlert, event models.AlertEvent) bool {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Webhook Error: Failed to encode event %s, error: %v", event.EventID, err)
+		return false
+	}
+
+	delay := w.Backoff
+	for attempt := 1; attempt <= w.MaxAttempts; attempt++ {
+		statusCode, err := w.send(ctx, alert, event.EventID, body)
+		delivery := models.AlertDelivery{
+			AlertID:    alert.ID,
+			EventID:    event.EventID,
+			Attempt:    attempt,
+			StatusCode: statusCode,
+			Success:    err == nil,
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if result := db.DB.WithContext(ctx).Create(&delivery); result.Error != nil {
+			log.Printf("Webhook Error: Failed to log delivery of %s, error: %v", event.EventID, result.Error)
+		}
+
+		if err == nil {
+			log.Printf("Webhook: Delivered %s to alert %d on attempt %d", event.EventID, alert.ID, attempt)
+			return true
+		}
+		log.Printf("Webhook Error: Attempt %d/%d for %s failed, error: %v", attempt, w.MaxAttempts, event.EventID, err)
+		if !retryableWebhookStatus(statusCode) || errors.Is(err, errCallbackBlocked) || attempt == w.MaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+	log.Printf("Webhook Error: Giving up on %s for alert %d", event.EventID, alert.ID)
+	return false
+}
+
+func (w *WebhookSender) send(ctx context.Context, alert models.RateAlert, eventID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alert.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(alert.Secret, timestamp, body))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("callback responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryableWebhookStatus reports whether a failed attempt may succeed later;
+// 0 stands for a network error.
+func retryableWebhookStatus(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= 500
+}
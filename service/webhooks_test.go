@@ -0,0 +1,205 @@
+package service
+
+import (
+	"assignment1/db"
+	"assignment1/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useTestDB points db.DB at a fresh SQLite database for the test.
+func useTestDB(t *testing.T) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000"
+	conn, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("opening test database: %v", err)
+	}
+	if err := conn.AutoMigrate(&models.RateHistory{}, &models.RateAlert{}, &models.AlertDelivery{}); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+	previous := db.DB
+	db.DB = conn
+	t.Cleanup(func() {
+		db.DB = previous
+		if sqlDB, err := conn.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// localSender delivers to httptest receivers on the loopback interface.
+func localSender(maxAttempts int) *WebhookSender {
+	return NewWebhookSender(time.Second, maxAttempts, time.Millisecond, NewCallbackPolicy([]string{"127.0.0.1"}))
+}
+
+// receiver answers webhooks with statuses in turn, repeating the last one.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.statuses[min(len(r.requests), len(r.statuses)-1)]
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(status)
+}
+
+func (r *receiver) hits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+func deliveriesOf(t *testing.T, alertID uint) []models.AlertDelivery {
+	t.Helper()
+	var deliveries []models.AlertDelivery
+	if err := db.DB.Where("alert_id = ?", alertID).Order("id").Find(&deliveries).Error; err != nil {
+		t.Fatalf("loading deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestSignWebhookSignsTimestampAndBody(t *testing.T) {
+	body := []byte(`{"EventID":"alert-1-1718000000"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(`1718000000.{"EventID":"alert-1-1718000000"}`))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhook("s3cret", 1718000000, body); got != want {
+		t.Errorf("SignWebhook = %s, want %s", got, want)
+	}
+	if SignWebhook("s3cret", 1718000001, body) == want {
+		t.Error("signature does not cover the timestamp")
+	}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	useTestDB(t)
+	recv := &receiver{statuses: []int{http.StatusNoContent}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	alert := models.RateAlert{ID: 7, CallbackURL: server.URL, Secret: "s3cret"}
+	event := models.AlertEvent{EventID: "alert-7-1718000000", AlertID: 7, Base: "USD", Target: "EUR"}
+	if !localSender(3).Deliver(context.Background(), alert, event) {
+		t.Fatal("Deliver reported failure for a 204 response")
+	}
+
+	if recv.hits() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", recv.hits())
+	}
+	req, body := recv.requests[0], recv.bodies[0]
+	timestamp, err := strconv.ParseInt(req.Header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s header: %v", WebhookTimestampHeader, err)
+	}
+	if got, want := req.Header.Get(WebhookSignatureHeader), SignWebhook("s3cret", timestamp, body); got != want {
+		t.Errorf("%s = %s, want %s", WebhookSignatureHeader, got, want)
+	}
+	if got := req.Header.Get(WebhookEventHeader); got != event.EventID {
+		t.Errorf("%s = %s, want %s", WebhookEventHeader, got, event.EventID)
+	}
+	var sent models.AlertEvent
+	if err := json.Unmarshal(body, &sent); err != nil || sent.EventID != event.EventID {
+		t.Errorf("body %s does not carry the event, error: %v", body, err)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		delivered   bool
+	}{
+		{"server errors", []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}, 5, true},
+		{"rate limited", []int{http.StatusTooManyRequests, http.StatusAccepted}, 5, true},
+		{"client error", []int{http.StatusBadRequest, http.StatusOK}, 5, false},
+		{"gone", []int{http.StatusGone}, 5, false},
+		{"attempts exhausted", []int{http.StatusBadGateway}, 3, false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestDB(t)
+			recv := &receiver{statuses: tt.statuses}
+			server := httptest.NewServer(recv)
+			defer server.Close()
+
+			alert := models.RateAlert{ID: uint(i + 1), CallbackURL: server.URL, Secret: "s3cret"}
+			event := models.AlertEvent{EventID: "alert-retry", AlertID: alert.ID}
+			if got := localSender(tt.maxAttempts).Deliver(context.Background(), alert, event); got != tt.delivered {
+				t.Errorf("Deliver = %v, want %v", got, tt.delivered)
+			}
+
+			deliveries := deliveriesOf(t, alert.ID)
+			if len(deliveries) != recv.hits() {
+				t.Fatalf("%d deliveries logged for %d requests", len(deliveries), recv.hits())
+			}
+			for n, delivery := range deliveries {
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				if delivery.Attempt != n+1 || delivery.StatusCode != status || delivery.EventID != event.EventID {
+					t.Errorf("delivery %d = attempt %d status %d event %s, want attempt %d status %d",
+						n, delivery.Attempt, delivery.StatusCode, delivery.EventID, n+1, status)
+				}
+				if last := n == len(deliveries)-1; delivery.Success != (last && tt.delivered) {
+					t.Errorf("delivery %d success = %v", n, delivery.Success)
+				}
+			}
+			if want := expectedAttempts(tt.statuses, tt.maxAttempts); len(deliveries) != want {
+				t.Errorf("%d attempts, want %d", len(deliveries), want)
+			}
+		})
+	}
+}
+
+// expectedAttempts counts the attempts until a success, a non-retryable
+// status or the attempt limit.
+func expectedAttempts(statuses []int, maxAttempts int) int {
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		status := statuses[min(attempt-1, len(statuses)-1)]
+		if status < 300 || !retryableWebhookStatus(status) {
+			return attempt
+		}
+	}
+	return maxAttempts
+}
+
+func TestDeliverRefusesInternalAddresses(t *testing.T) {
+	useTestDB(t)
+	recv := &receiver{statuses: []int{http.StatusOK}}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	sender := NewWebhookSender(time.Second, 3, time.Millisecond, nil)
+	alert := models.RateAlert{ID: 1, CallbackURL: server.URL, Secret: "s3cret"}
+	if sender.Deliver(context.Background(), alert, models.AlertEvent{EventID: "alert-1-1", AlertID: 1}) {
+		t.Fatal("Deliver reached a loopback callback")
+	}
+	if recv.hits() != 0 {
+		t.Errorf("receiver got %d requests", recv.hits())
+	}
+	if deliveries := deliveriesOf(t, 1); len(deliveries) != 1 || deliveries[0].Error == "" {
+		t.Errorf("deliveries = %+v, want one failed attempt without retries", deliveries)
+	}
+}
@@ -0,0 +1,143 @@
+package setup
+
+import (
+	"assignment1/config"
+	"assignment1/provider"
+	"github.com/shopspring/decimal"
+	"log"
+	"time"
+)
+
+// providerSet is the combined provider used by the sync plus the individual
+// providers that need extra wiring once the service exists.
+type providerSet struct {
+	Provider provider.RateProvider
+	// Historical providers backfill the rate history at startup.
+	Historical []provider.HistoricalRateProvider
+	// Files trigger a sync when their file changes.
+	Files []*provider.FileProvider
+	// Monitored wrap the configured providers, in PROVIDERS order, and Sync
+	// wraps Provider, for the status endpoint.
+	Monitored []*provider.MonitoredProvider
+	Sync      *provider.MonitoredProvider
+	Mode      string
+}
+
+// buildProvider combines the providers listed in PROVIDERS according to
+// PROVIDER_MODE: "failover" (default) tries them in order, "consensus" queries
+// all of them and aggregates their quotes.
+func buildProvider(cfg *config.Config) providerSet {
+	var set providerSet
+	providers := make([]provider.RateProvider, 0, len(cfg.Providers))
+	for _, name := range cfg.Providers {
+		p := newProvider(name, cfg)
+		monitored := provider.NewMonitoredProvider(p)
+		providers = append(providers, monitored)
+		set.Monitored = append(set.Monitored, monitored)
+		switch p := p.(type) {
+		case *provider.ECBProvider:
+			if p.HistoryURL != "" {
+				set.Historical = append(set.Historical, p)
+			}
+		case *provider.FileProvider:
+			set.Files = append(set.Files, p)
+		}
+	}
+
+	switch cfg.ProviderMode {
+	case "", "failover":
+	case "consensus":
+		method := cfg.ConsensusMethod
+		if method == "" {
+			method = provider.ConsensusMedian
+		}
+		if method != provider.ConsensusMedian && method != provider.ConsensusTrimmedMean {
+			log.Fatalf("invalid CONSENSUS_METHOD %q, expected median or trimmed-mean", method)
+		}
+		log.Printf("Rate providers configured for %s consensus: %v", method, cfg.Providers)
+		set.Mode = provider.ConsensusProviderName
+		set.Sync = provider.NewMonitoredProvider(&provider.ConsensusProvider{
+			Providers:    providers,
+			Method:       method,
+			TrimFraction: decimal.NewFromFloat(cfg.ConsensusTrimFraction),
+			MaxDeviation: decimal.NewFromFloat(cfg.ConsensusMaxDeviation),
+			MinProviders: cfg.ConsensusMinProviders,
+		})
+		set.Provider = set.Sync
+		return set
+	default:
+		log.Fatalf("invalid PROVIDER_MODE %q, expected failover or consensus", cfg.ProviderMode)
+	}
+
+	log.Printf("Rate providers configured in failover order: %v", cfg.Providers)
+	set.Mode = provider.FailoverProviderName
+	completeness := make(map[string]provider.Completeness, len(cfg.ProviderChecks))
+	for name, check := range cfg.ProviderChecks {
+		completeness[name] = provider.Completeness{MinRates: check.MinRates, RequiredCurrencies: check.RequiredCurrencies}
+	}
+	set.Sync = provider.NewMonitoredProvider(&provider.FailoverProvider{
+		Providers:    providers,
+		BaseCurrency: cfg.GlobalBaseCurrency,
+		Completeness: completeness,
+	})
+	set.Provider = set.Sync
+	return set
+}
+
+func newProvider(name string, cfg *config.Config) provider.RateProvider {
+	switch name {
+	case provider.OpenExchangeProviderName:
+		return &provider.OpenExchangeProvider{
+			URL:     cfg.ExchangeURL,
+			AppId:   cfg.ExchangeAppId,
+			Adapter: &provider.OpenExchangeAdapter{},
+			Client:  newHTTPClient(name, cfg),
+		}
+	case provider.ECBProviderName:
+		url := cfg.ECBURL
+		if url == "" {
+			url = provider.ECBDailyURL
+		}
+		return &provider.ECBProvider{
+			URL:        url,
+			HistoryURL: ecbHistoryURL(cfg.ECBHistory),
+			Adapter:    &provider.ECBAdapter{BaseCurrency: cfg.GlobalBaseCurrency},
+			Client:     newHTTPClient(name, cfg),
+		}
+	case provider.FileProviderName:
+		if cfg.RatesFile == "" {
+			log.Fatalf("RATES_FILE must be set to use the %s provider", provider.FileProviderName)
+		}
+		return provider.NewFileProvider(cfg.RatesFile, time.Duration(cfg.RatesFilePollSeconds)*time.Second)
+	default:
+		log.Fatalf("unknown rate provider %q in PROVIDERS", name)
+		return nil
+	}
+}
+
+// newHTTPClient gives each network provider its own client, so one failing
+// upstream only opens its own circuit.
+func newHTTPClient(name string, cfg *config.Config) *provider.HTTPClient {
+	return provider.NewHTTPClient(name, provider.HTTPClientConfig{
+		Timeout:          time.Duration(cfg.ProviderHTTP.TimeoutSeconds) * time.Second,
+		MaxRetries:       cfg.ProviderHTTP.MaxRetries,
+		BaseBackoff:      time.Duration(cfg.ProviderHTTP.BackoffMillis) * time.Millisecond,
+		MaxBackoff:       time.Duration(cfg.ProviderHTTP.MaxBackoffSeconds) * time.Second,
+		FailureThreshold: cfg.ProviderHTTP.CircuitFailureThreshold,
+		OpenTimeout:      time.Duration(cfg.ProviderHTTP.CircuitOpenSeconds) * time.Second,
+	})
+}
+
+// ecbHistoryURL maps ECB_HISTORY ("90d", "full" or a URL) to a feed URL.
+func ecbHistoryURL(history string) string {
+	switch history {
+	case "", "none":
+		return ""
+	case "90d":
+		return provider.ECBHistory90DURL
+	case "full":
+		return provider.ECBHistoryFullURL
+	default:
+		return history
+	}
+}
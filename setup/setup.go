@@ -4,11 +4,14 @@ import (
 	"assignment1/api"
 	"assignment1/cache"
 	"assignment1/config"
+	"assignment1/currency"
 	"assignment1/db"
 	ratepb "assignment1/grpc/proto"
 	"assignment1/middleware"
-	"assignment1/provider"
+	"assignment1/pricing"
 	"assignment1/service"
+	"assignment1/utility"
+	"context"
 	"google.golang.org/grpc"
 	"log"
 	"net"
@@ -28,11 +31,7 @@ func Initialize() *App {
 
 	db.Connect(cfg.DBUrl)
 
-	prov := &provider.OpenExchangeProvider{
-		URL:     cfg.ExchangeURL,
-		AppId:   cfg.ExchangeAppId,
-		Adapter: &provider.OpenExchangeAdapter{},
-	}
+	providers := buildProvider(cfg)
 
 	// For in-memory:
 	//c := cache.NewInMemoryCache()
@@ -40,26 +39,95 @@ func Initialize() *App {
 	// For Redis:
 	c := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
 
+	roundingMode := utility.RoundHalfUp
+	if cfg.RoundingMode != "" {
+		mode, err := utility.ParseRoundingMode(cfg.RoundingMode)
+		if err != nil {
+			log.Fatalf("invalid ROUNDING_MODE: %v", err)
+		}
+		roundingMode = mode
+	}
+
+	var spreads *pricing.Spreads
+	if cfg.SpreadsFile != "" {
+		var err error
+		spreads, err = pricing.LoadSpreads(cfg.SpreadsFile)
+		if err != nil {
+			log.Fatalf("invalid SPREADS_FILE: %v", err)
+		}
+		log.Printf("Loaded spreads from %s", cfg.SpreadsFile)
+	}
+
+	staleness := currency.StalenessPolicy{
+		DefaultMaxAge: time.Duration(cfg.RateMaxAgeSeconds) * time.Second,
+		MaxAge:        make(map[string]time.Duration, len(cfg.RateMaxAgePerCurrency)),
+	}
+	for code, secs := range cfg.RateMaxAgePerCurrency {
+		staleness.MaxAge[code] = time.Duration(secs) * time.Second
+	}
+
 	svc := &service.RateService{
-		Provider:            prov,
+		Provider:            providers.Provider,
 		Expiry:              time.Duration(cfg.CacheExpiry) * time.Second,
 		Cache:               c,
 		BackgroundTaskTimer: cfg.BackgroundTaskTimer,
 		GlobalBaseCurrency:  cfg.GlobalBaseCurrency,
+		RoundingMode:        roundingMode,
+		Precision:           currency.PrecisionPolicy{RateSignificantDigits: int32(cfg.RateSignificantDigits)},
+		Currencies:          currency.Default(),
+		Spreads:             spreads,
+		Staleness:           staleness,
+		Updates:             service.NewRateHub(0),
+		Webhooks: service.NewWebhookSender(
+			time.Duration(cfg.AlertWebhookTimeoutSeconds)*time.Second,
+			cfg.AlertWebhookMaxAttempts,
+			time.Duration(cfg.AlertWebhookBackoffMillis)*time.Millisecond,
+			service.NewCallbackPolicy(cfg.AlertWebhookAllowedHosts),
+		),
+	}
+
+	svc.LoadOverrides(context.Background())
+
+	for _, h := range providers.Historical {
+		go svc.BackfillHistory(context.Background(), h)
+	}
+	for _, f := range providers.Files {
+		f.OnChange = svc.SyncNow
+		f.Watch()
 	}
 
+	currencySvc := &service.CurrencyService{
+		Registry:           currency.Default(),
+		GlobalBaseCurrency: cfg.GlobalBaseCurrency,
+	}
+
+	statusSvc := &service.StatusService{
+		Mode:         providers.Mode,
+		Sync:         providers.Sync,
+		Providers:    providers.Monitored,
+		SyncInterval: cfg.BackgroundTaskTimer * time.Minute,
+	}
+
+	clientKeys := middleware.NewClientKeys(cfg.APIKeys)
+	log.Printf("Loaded %d API keys", len(cfg.APIKeys))
+
 	r := gin.Default()
 	r.Use(middleware.Logger())
+	r.Use(middleware.ClientAuth(clientKeys))
 
-	api.RegisterRoutes(r, svc)
+	api.RegisterRoutes(r, svc, currencySvc, statusSvc, cfg.AdminToken, api.StreamConfig{
+		Heartbeat:      time.Duration(cfg.StreamHeartbeatSeconds) * time.Second,
+		AllowedOrigins: cfg.StreamAllowedOrigins,
+	})
 
 	go func() {
 		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
 		if err != nil {
 			log.Fatalf("failed to listen: %v", err)
 		}
-		grpcServer := grpc.NewServer()
-		ratepb.RegisterRateServiceServer(grpcServer, api.NewRateGRPCServer(svc))
+		unary, stream := middleware.GRPCClientAuth(clientKeys)
+		grpcServer := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+		ratepb.RegisterRateServiceServer(grpcServer, api.NewRateGRPCServer(svc, currencySvc))
 		log.Printf("gRPC server listening on %s", ":"+cfg.GRPCPort)
 		if err := grpcServer.Serve(lis); err != nil {
 			log.Fatalf("failed to serve gRPC: %v", err)
@@ -0,0 +1,37 @@
+package utility
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+)
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half-up"
+	RoundHalfEven RoundingMode = "half-even"
+	RoundDown     RoundingMode = "down"
+)
+
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	switch mode := RoundingMode(value); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported rounding mode %q, expected half-up, half-even or down", value)
+	}
+}
+
+// RoundWithMode rounds x to the given number of decimal places. Half-up rounds
+// ties away from zero, half-even rounds ties to the nearest even digit and
+// down truncates towards zero.
+func RoundWithMode(x decimal.Decimal, places int32, mode RoundingMode) decimal.Decimal {
+	switch mode {
+	case RoundHalfEven:
+		return x.RoundBank(places)
+	case RoundDown:
+		return x.Truncate(places)
+	default:
+		return x.Round(places)
+	}
+}
@@ -1,9 +1,14 @@
 package utility
 
-import "math"
+import "github.com/shopspring/decimal"
 
-func Round[T ~float32 | ~float64](x T, places int) T {
-	factor := math.Pow(10, float64(places))
-	rounded := math.Round(float64(x)*factor) / factor
-	return T(rounded)
+// RoundSignificant rounds x to the given number of significant digits, e.g.
+// 0.000123456 to 3 digits is 0.000123 and 123456 is 123000.
+func RoundSignificant(x decimal.Decimal, digits int32) decimal.Decimal {
+	if x.IsZero() || digits <= 0 {
+		return x
+	}
+	// Position of the most significant digit relative to the decimal point.
+	magnitude := int32(x.NumDigits()) + x.Exponent() - 1
+	return x.Round(digits - 1 - magnitude)
 }